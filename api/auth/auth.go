@@ -0,0 +1,261 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package auth contains the authentication methods available to
+// the romulus API clients.
+package auth
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/go-macaroon-bakery/macaroon-bakery/v3/httpbakery"
+	"github.com/juju/errors"
+	"gopkg.in/macaroon.v2"
+)
+
+// HTTPClient defines the interface of the http client used
+// to send API requests.
+type HTTPClient interface {
+	// Do sends the given HTTP request and returns its response.
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Authenticator defines a method of authenticating API requests.
+type Authenticator interface {
+	// HTTPClient returns the http client that should be used to send
+	// requests when no other client has been specified.
+	HTTPClient() (HTTPClient, error)
+
+	// Authenticate adds credentials to the request before it is sent.
+	Authenticate(req *http.Request) error
+}
+
+// DischargeRequiredError is returned when the service requires a
+// macaroon discharge that the configured authentication method is not
+// allowed to obtain, because doing so would require interaction.
+type DischargeRequiredError struct {
+	// URL holds the URL of the request that required the discharge.
+	URL string
+	// Reason holds the underlying error, if any.
+	Reason error
+}
+
+func (e *DischargeRequiredError) Error() string {
+	if e.Reason != nil {
+		return fmt.Sprintf("discharge required for %q but interaction is not allowed: %v", e.URL, e.Reason)
+	}
+	return fmt.Sprintf("discharge required for %q but interaction is not allowed", e.URL)
+}
+
+// IsDischargeRequired indicates whether the error is a DischargeRequiredError.
+func IsDischargeRequired(err error) bool {
+	_, ok := errors.Cause(err).(*DischargeRequiredError)
+	return ok
+}
+
+// NewClient returns an http client that adds credentials to each request
+// using the authenticator before sending it with h. If h is nil the
+// authenticator's own http client is used.
+func NewClient(a Authenticator, h HTTPClient) (HTTPClient, error) {
+	if h == nil {
+		var err error
+		h, err = a.HTTPClient()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return &client{auth: a, h: h}, nil
+}
+
+type client struct {
+	auth Authenticator
+	h    HTTPClient
+}
+
+// Do implements the HTTPClient interface.
+func (c *client) Do(req *http.Request) (*http.Response, error) {
+	if err := c.auth.Authenticate(req); err != nil {
+		return nil, errors.Annotate(err, "failed to authenticate request")
+	}
+	resp, err := c.h.Do(req)
+	if err != nil {
+		if irErr, ok := interactionRequired(err); ok {
+			return nil, irErr
+		}
+		if httpbakery.IsInteractionError(errors.Cause(err)) {
+			return nil, &DischargeRequiredError{URL: req.URL.String(), Reason: errors.Cause(err)}
+		}
+		return nil, err
+	}
+	if isDischargeRequired(resp) {
+		resp.Body.Close()
+		return nil, &DischargeRequiredError{URL: req.URL.String()}
+	}
+	return resp, nil
+}
+
+// isDischargeRequired checks whether the response holds a
+// discharge-required error. The response body is left unchanged if
+// it does not.
+func isDischargeRequired(resp *http.Response) bool {
+	switch {
+	case resp.StatusCode == http.StatusProxyAuthRequired:
+	case resp.StatusCode == http.StatusUnauthorized && resp.Header.Get("WWW-Authenticate") == "Macaroon":
+	default:
+		return false
+	}
+	if resp.Body == nil {
+		return false
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return false
+	}
+	var bakeryErr httpbakery.Error
+	if err := json.Unmarshal(data, &bakeryErr); err != nil {
+		return false
+	}
+	return bakeryErr.Code == httpbakery.ErrDischargeRequired
+}
+
+// Bakery returns an authenticator that acquires macaroon discharges
+// using the provided bakery client. If the client has no interaction
+// methods, requests that require interaction fail with a
+// DischargeRequiredError.
+func Bakery(c *httpbakery.Client) Authenticator {
+	return &bakeryAuthenticator{c: c}
+}
+
+type bakeryAuthenticator struct {
+	c *httpbakery.Client
+}
+
+// HTTPClient implements the Authenticator interface.
+func (a *bakeryAuthenticator) HTTPClient() (HTTPClient, error) {
+	return a.c, nil
+}
+
+// Authenticate implements the Authenticator interface. The bakery
+// client needs a seekable request body so that the request can be
+// repeated once a discharge has been acquired.
+func (a *bakeryAuthenticator) Authenticate(req *http.Request) error {
+	if req.Body == nil {
+		return nil
+	}
+	if _, ok := req.Body.(io.ReadSeeker); ok {
+		return nil
+	}
+	data, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Body = seekableBody{bytes.NewReader(data)}
+	return nil
+}
+
+// seekableBody is a request body that can be rewound.
+type seekableBody struct {
+	*bytes.Reader
+}
+
+// Close implements io.Closer.
+func (seekableBody) Close() error {
+	return nil
+}
+
+// Macaroons returns an authenticator that attaches the given
+// pre-discharged macaroon slices to each request.
+func Macaroons(ms ...macaroon.Slice) Authenticator {
+	return &macaroonAuthenticator{ms: ms}
+}
+
+type macaroonAuthenticator struct {
+	ms []macaroon.Slice
+}
+
+// HTTPClient implements the Authenticator interface.
+func (a *macaroonAuthenticator) HTTPClient() (HTTPClient, error) {
+	return &http.Client{}, nil
+}
+
+// Authenticate implements the Authenticator interface.
+func (a *macaroonAuthenticator) Authenticate(req *http.Request) error {
+	for _, ms := range a.ms {
+		cookie, err := httpbakery.NewCookie(nil, ms)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		req.AddCookie(cookie)
+	}
+	return nil
+}
+
+// BearerToken returns an authenticator that sets the Authorization
+// header of each request to the given bearer token.
+func BearerToken(token string) Authenticator {
+	return &tokenAuthenticator{token: token}
+}
+
+type tokenAuthenticator struct {
+	token string
+}
+
+// HTTPClient implements the Authenticator interface.
+func (a *tokenAuthenticator) HTTPClient() (HTTPClient, error) {
+	return &http.Client{}, nil
+}
+
+// Authenticate implements the Authenticator interface.
+func (a *tokenAuthenticator) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+// ClientCertificate returns an authenticator that authenticates the
+// client with the given TLS certificate. If rootCAs is nil, the host's
+// root CA set is used to verify the service.
+func ClientCertificate(cert tls.Certificate, rootCAs *x509.CertPool) Authenticator {
+	return &tlsAuthenticator{cert: cert, rootCAs: rootCAs}
+}
+
+type tlsAuthenticator struct {
+	cert    tls.Certificate
+	rootCAs *x509.CertPool
+}
+
+// HTTPClient implements the Authenticator interface.
+func (a *tlsAuthenticator) HTTPClient() (HTTPClient, error) {
+	if len(a.cert.Certificate) == 0 {
+		return nil, errors.New("no client certificate specified")
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		Certificates: []tls.Certificate{a.cert},
+		RootCAs:      a.rootCAs,
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// Authenticate implements the Authenticator interface.
+func (a *tlsAuthenticator) Authenticate(*http.Request) error {
+	return nil
+}
+
+// discardClose reads any remaining data from the response body and closes it.
+func discardClose(response *http.Response) {
+	if response == nil || response.Body == nil {
+		return
+	}
+	io.Copy(ioutil.Discard, response.Body)
+	response.Body.Close()
+}
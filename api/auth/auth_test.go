@@ -0,0 +1,243 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auth_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-macaroon-bakery/macaroon-bakery/v3/bakery"
+	"github.com/go-macaroon-bakery/macaroon-bakery/v3/bakery/checkers"
+	"github.com/go-macaroon-bakery/macaroon-bakery/v3/bakerytest"
+	"github.com/go-macaroon-bakery/macaroon-bakery/v3/httpbakery"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/macaroon.v2"
+
+	"github.com/juju/romulus/api/auth"
+)
+
+type authSuite struct{}
+
+var _ = gc.Suite(&authSuite{})
+
+func (s *authSuite) TestBearerToken(c *gc.C) {
+	h := &mockClient{RespCode: http.StatusOK}
+	client, err := auth.NewClient(auth.BearerToken("secret"), h)
+	c.Assert(err, jc.ErrorIsNil)
+	req, err := http.NewRequest("GET", "https://example.com/wallet", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := client.Do(req)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	h.CheckCalls(c, []jujutesting.StubCall{{
+		FuncName: "Do",
+		Args:     []interface{}{"https://example.com/wallet", "Bearer secret"},
+	}})
+}
+
+func (s *authSuite) TestMacaroons(c *gc.C) {
+	m, err := macaroon.New([]byte("key"), []byte("id"), "loc", macaroon.LatestVersion)
+	c.Assert(err, jc.ErrorIsNil)
+	h := &mockClient{RespCode: http.StatusOK}
+	client, err := auth.NewClient(auth.Macaroons(macaroon.Slice{m}), h)
+	c.Assert(err, jc.ErrorIsNil)
+	req, err := http.NewRequest("GET", "https://example.com/wallet", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.Do(req)
+	c.Assert(err, jc.ErrorIsNil)
+	ms := httpbakery.RequestMacaroons(req)
+	c.Assert(ms, gc.HasLen, 1)
+	c.Assert(ms[0], gc.HasLen, 1)
+	c.Assert(ms[0][0].Signature(), jc.DeepEquals, m.Signature())
+}
+
+func (s *authSuite) TestDischargeRequired(c *gc.C) {
+	body, err := json.Marshal(httpbakery.Error{
+		Code:    httpbakery.ErrDischargeRequired,
+		Message: "verification failed",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	h := &mockClient{
+		RespCode: http.StatusUnauthorized,
+		RespBody: body,
+		Header:   http.Header{"Www-Authenticate": []string{"Macaroon"}},
+	}
+	client, err := auth.NewClient(auth.BearerToken("secret"), h)
+	c.Assert(err, jc.ErrorIsNil)
+	req, err := http.NewRequest("GET", "https://example.com/wallet", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.Do(req)
+	c.Assert(err, gc.ErrorMatches, `discharge required for "https://example.com/wallet" but interaction is not allowed`)
+	c.Assert(auth.IsDischargeRequired(err), jc.IsTrue)
+}
+
+func (s *authSuite) TestUnauthorizedBodyPreserved(c *gc.C) {
+	h := &mockClient{
+		RespCode: http.StatusUnauthorized,
+		RespBody: []byte(`{"error":"not allowed"}`),
+		Header:   http.Header{"Www-Authenticate": []string{"Macaroon"}},
+	}
+	client, err := auth.NewClient(auth.BearerToken("secret"), h)
+	c.Assert(err, jc.ErrorIsNil)
+	req, err := http.NewRequest("GET", "https://example.com/wallet", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := client.Do(req)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, `{"error":"not allowed"}`)
+}
+
+func (s *authSuite) TestBakeryWithoutInteraction(c *gc.C) {
+	d, server := newDischargeRequiredServer(c, 0)
+	defer d.Close()
+	defer server.Close()
+
+	client, err := auth.NewClient(auth.Bakery(httpbakery.NewClient()), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	req, err := http.NewRequest("GET", server.URL+"/wallet", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.Do(req)
+	c.Assert(err, gc.ErrorMatches, `discharge required for ".*/wallet" but interaction is not allowed: .*`)
+	c.Assert(auth.IsDischargeRequired(err), jc.IsTrue)
+}
+
+func (s *authSuite) TestClientCertificate(c *gc.C) {
+	cert := newCertificate(c)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if len(req.TLS.PeerCertificates) != 1 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(req.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	client, err := auth.NewClient(auth.ClientCertificate(cert, roots), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	req, err := http.NewRequest("GET", server.URL, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := client.Do(req)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	data, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "romulus-test")
+}
+
+func (s *authSuite) TestClientCertificateMissing(c *gc.C) {
+	_, err := auth.NewClient(auth.ClientCertificate(tls.Certificate{}, nil), nil)
+	c.Assert(err, gc.ErrorMatches, "no client certificate specified")
+}
+
+// newDischargeRequiredServer returns a discharger that requires
+// browser-window interaction and a server that requires a discharge from
+// it. The discharger's wait token endpoint fails the given number of
+// times before returning a valid token.
+func newDischargeRequiredServer(c *gc.C, waitFailures int) (*bakerytest.Discharger, *httptest.Server) {
+	return newDischargeRequiredServerWithStatus(c, waitFailures, http.StatusNotFound)
+}
+
+// newDischargeRequiredServerWithStatus is like newDischargeRequiredServer
+// but the wait token endpoint fails with the given status code.
+func newDischargeRequiredServerWithStatus(c *gc.C, waitFailures, status int) (*bakerytest.Discharger, *httptest.Server) {
+	d := bakerytest.NewDischarger(nil)
+	d.Checker = httpbakery.ThirdPartyCaveatCheckerFunc(func(ctx context.Context, req *http.Request, cav *bakery.ThirdPartyCaveatInfo, token *httpbakery.DischargeToken) ([]checkers.Caveat, error) {
+		if token != nil && string(token.Value) == "ok" {
+			return nil, nil
+		}
+		err := httpbakery.NewInteractionRequiredError(nil, req)
+		httpbakery.SetWebBrowserInteraction(err, d.Location()+"/visit", d.Location()+"/wait")
+		return nil, err
+	})
+	waits := 0
+	d.Mux.HandlerFunc("GET", "/wait", func(w http.ResponseWriter, req *http.Request) {
+		if waits++; waits <= waitFailures {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(httpbakery.WaitTokenResponse{
+			Kind:  httpbakery.WebBrowserInteractionKind,
+			Token: "ok",
+		})
+	})
+
+	b := bakery.New(bakery.BakeryParams{
+		Location: "omnibus",
+		Locator:  d,
+		Key:      bakery.MustGenerateKey(),
+	})
+	op := bakery.Op{Entity: "wallet", Action: "read"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, err := b.Checker.Auth(httpbakery.RequestMacaroons(req)...).Allow(req.Context(), op)
+		if err == nil {
+			w.Write([]byte("authorized"))
+			return
+		}
+		m, err := b.Oven.NewMacaroon(req.Context(), bakery.LatestVersion, []checkers.Caveat{{
+			Location:  d.Location(),
+			Condition: "is-authenticated-user",
+		}}, op)
+		c.Check(err, jc.ErrorIsNil)
+		httpbakery.WriteError(req.Context(), w, httpbakery.NewDischargeRequiredError(httpbakery.DischargeRequiredErrorParams{
+			Macaroon: m,
+			Request:  req,
+		}))
+	}))
+	return d, server
+}
+
+func newCertificate(c *gc.C) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, jc.ErrorIsNil)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "romulus-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	c.Assert(err, jc.ErrorIsNil)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+type mockClient struct {
+	jujutesting.Stub
+
+	RespCode int
+	RespBody []byte
+	Header   http.Header
+}
+
+func (c *mockClient) Do(req *http.Request) (*http.Response, error) {
+	c.Stub.MethodCall(c, "Do", req.URL.String(), req.Header.Get("Authorization"))
+	resp := &http.Response{
+		StatusCode: c.RespCode,
+		Header:     c.Header,
+		Body:       ioutil.NopCloser(bytes.NewReader(c.RespBody)),
+	}
+	return resp, c.Stub.NextErr()
+}
@@ -0,0 +1,223 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-macaroon-bakery/macaroon-bakery/v3/httpbakery"
+	"github.com/juju/errors"
+)
+
+// InteractionRequiredError is returned when a discharge requires
+// user interaction and the interaction policy does not allow it.
+type InteractionRequiredError struct {
+	// URL holds the URL the user must visit to complete the
+	// interaction.
+	URL *url.URL
+}
+
+func (e *InteractionRequiredError) Error() string {
+	return fmt.Sprintf("interaction required: visit %s", e.URL)
+}
+
+// IsInteractionRequired indicates whether the error is an
+// InteractionRequiredError.
+func IsInteractionRequired(err error) bool {
+	_, ok := errors.Cause(err).(*InteractionRequiredError)
+	return ok
+}
+
+// interactionRequired returns the InteractionRequiredError held
+// by a bakery interaction error, if any.
+func interactionRequired(err error) (*InteractionRequiredError, bool) {
+	interactionErr, ok := errors.Cause(err).(*httpbakery.InteractionError)
+	if !ok {
+		return nil, false
+	}
+	irErr, ok := errors.Cause(interactionErr.Reason).(*InteractionRequiredError)
+	return irErr, ok
+}
+
+var (
+	_ httpbakery.Interactor       = (*failInteractor)(nil)
+	_ httpbakery.LegacyInteractor = (*failInteractor)(nil)
+	_ httpbakery.Interactor       = (*pollingInteractor)(nil)
+	_ httpbakery.LegacyInteractor = (*pollingInteractor)(nil)
+)
+
+// Interactive returns an interactor that completes discharges by
+// calling visit with the URL the user must visit.
+func Interactive(visit func(*url.URL) error) httpbakery.Interactor {
+	return httpbakery.WebBrowserInteractor{OpenWebBrowser: visit}
+}
+
+// NonInteractive returns an interactor that fails any discharge
+// requiring interaction with an InteractionRequiredError carrying the
+// URL the user must visit.
+func NonInteractive() httpbakery.Interactor {
+	return &failInteractor{}
+}
+
+type failInteractor struct{}
+
+// Kind implements httpbakery.Interactor.
+func (*failInteractor) Kind() string {
+	return httpbakery.WebBrowserInteractionKind
+}
+
+// Interact implements httpbakery.Interactor.
+func (i *failInteractor) Interact(ctx context.Context, client *httpbakery.Client, location string, irErr *httpbakery.Error) (*httpbakery.DischargeToken, error) {
+	visitURL, _, err := browserInteraction(location, irErr)
+	if err != nil {
+		return nil, err
+	}
+	return nil, &httpbakery.InteractionError{Reason: &InteractionRequiredError{URL: visitURL}}
+}
+
+// LegacyInteract implements httpbakery.LegacyInteractor.
+func (*failInteractor) LegacyInteract(ctx context.Context, client *httpbakery.Client, location string, visitURL *url.URL) error {
+	return &InteractionRequiredError{URL: visitURL}
+}
+
+// Polling returns an interactor suitable for headless clients. The URL
+// the user must visit is reported with notify, after which the
+// discharger is polled every interval until the user has completed the
+// interaction or the timeout expires. Polling stops early if the
+// discharger responds in a way that shows the interaction will never
+// complete, such as when the user denies access. Both interval and
+// timeout must be positive.
+func Polling(notify func(*url.URL) error, interval, timeout time.Duration) (httpbakery.Interactor, error) {
+	if interval <= 0 {
+		return nil, errors.NotValidf("non-positive polling interval %v", interval)
+	}
+	if timeout <= 0 {
+		return nil, errors.NotValidf("non-positive polling timeout %v", timeout)
+	}
+	return &pollingInteractor{
+		notify:   notify,
+		interval: interval,
+		timeout:  timeout,
+	}, nil
+}
+
+type pollingInteractor struct {
+	notify   func(*url.URL) error
+	interval time.Duration
+	timeout  time.Duration
+}
+
+// Kind implements httpbakery.Interactor.
+func (*pollingInteractor) Kind() string {
+	return httpbakery.WebBrowserInteractionKind
+}
+
+// Interact implements httpbakery.Interactor.
+func (i *pollingInteractor) Interact(ctx context.Context, client *httpbakery.Client, location string, irErr *httpbakery.Error) (*httpbakery.DischargeToken, error) {
+	visitURL, waitTokenURL, err := browserInteraction(location, irErr)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.notify(visitURL); err != nil {
+		return nil, errors.Trace(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, i.timeout)
+	defer cancel()
+	for {
+		token, retry, err := i.waitToken(ctx, client, waitTokenURL)
+		if err == nil {
+			return token, nil
+		}
+		if !retry {
+			return nil, errors.Annotatef(err, "interaction at %s failed", visitURL)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, errors.Annotatef(err, "timed out waiting for interaction at %s", visitURL)
+		case <-time.After(i.interval):
+		}
+	}
+}
+
+// waitToken makes a single attempt to retrieve the discharge token. If
+// it fails, retry reports whether a later attempt may succeed.
+func (i *pollingInteractor) waitToken(ctx context.Context, client *httpbakery.Client, waitTokenURL *url.URL) (_ *httpbakery.DischargeToken, retry bool, _ error) {
+	req, err := http.NewRequest("GET", waitTokenURL.String(), nil)
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	resp, err := client.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, true, errors.Trace(err)
+	}
+	defer discardClose(resp)
+	if resp.StatusCode != http.StatusOK {
+		err := errors.Errorf("interaction not completed: %s", http.StatusText(resp.StatusCode))
+		return nil, pending(resp.StatusCode), err
+	}
+	var tokenResp httpbakery.WaitTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return nil, false, errors.Annotate(err, "failed to decode wait response")
+	}
+	value := []byte(tokenResp.Token)
+	if tokenResp.Token64 != "" {
+		value, err = base64.StdEncoding.DecodeString(tokenResp.Token64)
+		if err != nil {
+			return nil, false, errors.Annotate(err, "bad discharge token")
+		}
+	}
+	return &httpbakery.DischargeToken{
+		Kind:  tokenResp.Kind,
+		Value: value,
+	}, false, nil
+}
+
+// pending reports whether a wait response with the given status code
+// may be followed by a successful one: the interaction is not yet
+// known to the discharger, the request timed out or was rate limited,
+// or the discharger is temporarily failing. Other errors, such as the
+// user denying access, are final.
+func pending(statusCode int) bool {
+	switch statusCode {
+	case http.StatusNotFound, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	}
+	return statusCode >= http.StatusInternalServerError
+}
+
+// LegacyInteract implements httpbakery.LegacyInteractor. The bakery
+// client itself waits for the legacy interaction to complete.
+func (i *pollingInteractor) LegacyInteract(ctx context.Context, client *httpbakery.Client, location string, visitURL *url.URL) error {
+	return errors.Trace(i.notify(visitURL))
+}
+
+// browserInteraction returns the visit and wait token URLs held in the
+// interaction-required error.
+func browserInteraction(location string, irErr *httpbakery.Error) (*url.URL, *url.URL, error) {
+	var info httpbakery.WebBrowserInteractionInfo
+	if err := irErr.InteractionMethod(httpbakery.WebBrowserInteractionKind, &info); err != nil {
+		// Returned unwrapped so that the bakery client can try
+		// other interaction methods.
+		return nil, nil, err
+	}
+	base, err := url.Parse(location)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	visitURL, err := base.Parse(info.VisitURL)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "invalid visit URL")
+	}
+	waitTokenURL, err := base.Parse(info.WaitTokenURL)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "invalid wait URL")
+	}
+	return visitURL, waitTokenURL, nil
+}
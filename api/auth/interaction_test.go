@@ -0,0 +1,133 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auth_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-macaroon-bakery/macaroon-bakery/v3/httpbakery"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/romulus/api/auth"
+)
+
+type interactionSuite struct{}
+
+var _ = gc.Suite(&interactionSuite{})
+
+func (s *interactionSuite) TestNonInteractive(c *gc.C) {
+	d, server := newDischargeRequiredServer(c, 0)
+	defer d.Close()
+	defer server.Close()
+
+	bakeryClient := httpbakery.NewClient()
+	bakeryClient.AddInteractor(auth.NonInteractive())
+	client, err := auth.NewClient(auth.Bakery(bakeryClient), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	req, err := http.NewRequest("GET", server.URL+"/wallet", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.Do(req)
+	c.Assert(err, gc.ErrorMatches, `interaction required: visit .*/visit`)
+	c.Assert(auth.IsInteractionRequired(err), jc.IsTrue)
+	irErr := err.(*auth.InteractionRequiredError)
+	c.Assert(irErr.URL.String(), gc.Equals, d.Location()+"/visit")
+}
+
+func (s *interactionSuite) TestInteractive(c *gc.C) {
+	d, server := newDischargeRequiredServer(c, 0)
+	defer d.Close()
+	defer server.Close()
+
+	var visited []string
+	bakeryClient := httpbakery.NewClient()
+	bakeryClient.AddInteractor(auth.Interactive(func(u *url.URL) error {
+		visited = append(visited, u.String())
+		return nil
+	}))
+	client, err := auth.NewClient(auth.Bakery(bakeryClient), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	req, err := http.NewRequest("GET", server.URL+"/wallet", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := client.Do(req)
+	c.Assert(err, jc.ErrorIsNil)
+	resp.Body.Close()
+	c.Assert(visited, jc.DeepEquals, []string{d.Location() + "/visit"})
+}
+
+func (s *interactionSuite) TestPolling(c *gc.C) {
+	d, server := newDischargeRequiredServer(c, 2)
+	defer d.Close()
+	defer server.Close()
+
+	var notified []string
+	polling, err := auth.Polling(func(u *url.URL) error {
+		notified = append(notified, u.String())
+		return nil
+	}, time.Millisecond, time.Second)
+	c.Assert(err, jc.ErrorIsNil)
+	bakeryClient := httpbakery.NewClient()
+	bakeryClient.AddInteractor(polling)
+	client, err := auth.NewClient(auth.Bakery(bakeryClient), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	req, err := http.NewRequest("GET", server.URL+"/wallet", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := client.Do(req)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "authorized")
+	c.Assert(notified, jc.DeepEquals, []string{d.Location() + "/visit"})
+}
+
+func (s *interactionSuite) TestPollingTimeout(c *gc.C) {
+	d, server := newDischargeRequiredServer(c, 1000)
+	defer d.Close()
+	defer server.Close()
+
+	polling, err := auth.Polling(func(*url.URL) error {
+		return nil
+	}, time.Second, time.Millisecond)
+	c.Assert(err, jc.ErrorIsNil)
+	bakeryClient := httpbakery.NewClient()
+	bakeryClient.AddInteractor(polling)
+	client, err := auth.NewClient(auth.Bakery(bakeryClient), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	req, err := http.NewRequest("GET", server.URL+"/wallet", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.Do(req)
+	c.Assert(err, gc.ErrorMatches, `.*timed out waiting for interaction at .*/visit.*`)
+}
+
+func (s *interactionSuite) TestPollingDenied(c *gc.C) {
+	d, server := newDischargeRequiredServerWithStatus(c, 1, http.StatusForbidden)
+	defer d.Close()
+	defer server.Close()
+
+	polling, err := auth.Polling(func(*url.URL) error {
+		return nil
+	}, time.Millisecond, time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	bakeryClient := httpbakery.NewClient()
+	bakeryClient.AddInteractor(polling)
+	client, err := auth.NewClient(auth.Bakery(bakeryClient), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	req, err := http.NewRequest("GET", server.URL+"/wallet", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.Do(req)
+	// The wait endpoint would succeed on a second attempt.
+	c.Assert(err, gc.ErrorMatches, `.*interaction at .*/visit failed: interaction not completed: Forbidden.*`)
+}
+
+func (s *interactionSuite) TestPollingNotValid(c *gc.C) {
+	notify := func(*url.URL) error { return nil }
+	_, err := auth.Polling(notify, 0, time.Second)
+	c.Assert(err, gc.ErrorMatches, `non-positive polling interval 0s not valid`)
+	_, err = auth.Polling(notify, time.Second, -time.Second)
+	c.Assert(err, gc.ErrorMatches, `non-positive polling timeout -1s not valid`)
+}
@@ -9,14 +9,25 @@ import (
 	"encoding/json"
 	"io"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/url"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-macaroon-bakery/macaroon-bakery/v3/httpbakery"
 	"github.com/juju/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/juju/romulus"
+	"github.com/juju/romulus/api/auth"
+	"github.com/juju/romulus/api/cache"
+	"github.com/juju/romulus/api/instrument"
+	"github.com/juju/romulus/api/logging"
+	"github.com/juju/romulus/api/ratelimit"
 	wireformat "github.com/juju/romulus/wireformat/budget"
 	"github.com/juju/romulus/wireformat/common"
 )
@@ -29,9 +40,48 @@ type httpClient interface {
 	Do(*http.Request) (*http.Response, error)
 }
 
+// Client defines the interface available to clients of the budget api.
+type Client interface {
+	CreateWallet(name string, limit string) (string, error)
+	ListWallets() (*wireformat.ListWalletsResponse, error)
+	ListWalletsPage(cursor string, limit int) (*wireformat.ListWalletsResponse, error)
+	SetWallet(wallet, limit string) (string, error)
+	GetWallet(wallet string) (*wireformat.WalletWithBudgets, error)
+	GetWalletPage(wallet, cursor string, limit int) (*wireformat.WalletWithBudgets, error)
+	DeleteWallet(wallet string, force bool) (string, error)
+	RenameWallet(wallet, name string) (string, error)
+	SetDefaultWallet(wallet string) (string, error)
+	GrantWallet(wallet, subject string, permission wireformat.Permission) (string, error)
+	RevokeWallet(wallet, subject string) (string, error)
+	ListWalletGrants(wallet string) ([]wireformat.WalletGrant, error)
+	GetCredit() (*wireformat.CreditResponse, error)
+	TopUp(amount, reference string) (string, error)
+	AllocateCredit(wallet, amount string) (string, error)
+	CreateBudget(wallet, limit string, model string, options ...BudgetOption) (string, error)
+	UpdateBudget(model, wallet, limit string, options ...BudgetOption) (string, error)
+	SetBudgetAlerts(model string, thresholds []int) (string, error)
+	DeleteBudget(model string) (string, error)
+	MoveBudget(model, wallet string) (*wireformat.WalletWithBudgets, *wireformat.WalletWithBudgets, error)
+	GetBudget(model string) (*wireformat.Budget, error)
+	ListBudgets(filter wireformat.ListBudgetsRequest) ([]wireformat.Budget, error)
+	Ledger(req wireformat.LedgerRequest) (*wireformat.LedgerResponse, error)
+	Wallets(pageSize int) *WalletIterator
+	Budgets(wallet string, pageSize int) *BudgetIterator
+	Bulk(ops []BulkOperation, config BulkConfig) ([]BulkResult, error)
+}
+
+var _ Client = (*client)(nil)
+
 type client struct {
-	apiRoot string
-	h       httpClient
+	apiRoot       string
+	h             httpClient
+	authenticator auth.Authenticator
+	observer      instrument.Observer
+	tracer        trace.TracerProvider
+	logger        logging.Logger
+	logLevel      logging.Level
+	cache         *cache.Cache
+	limiter       *ratelimit.Limiter
 }
 
 // ClientOption defines a function which configures a Client.
@@ -54,10 +104,70 @@ func APIRoot(apiRoot string) func(h *client) error {
 	}
 }
 
+// Authentication sets the method used to authenticate API requests.
+// If an http client is also specified, authenticated requests are sent
+// using that client.
+func Authentication(a auth.Authenticator) func(h *client) error {
+	return func(c *client) error {
+		c.authenticator = a
+		return nil
+	}
+}
+
+// Instrument sets the observer that is notified of every request sent
+// by the client. Operations are named after the client method, such as
+// "budget.CreateWallet".
+func Instrument(o instrument.Observer) func(h *client) error {
+	return func(c *client) error {
+		c.observer = o
+		return nil
+	}
+}
+
+// TracerProvider sets the tracer provider used to trace the requests
+// sent by the client. If it is not set the global tracer provider is
+// used. Each request is traced in a span named after its operation,
+// whose context is propagated to the service in the request headers.
+func TracerProvider(tp trace.TracerProvider) func(h *client) error {
+	return func(c *client) error {
+		c.tracer = tp
+		return nil
+	}
+}
+
+// Logging sets the logger passed a record of every request sent by the
+// client. The level determines whether headers and bodies are logged;
+// credentials, macaroons and authorization data are always redacted.
+func Logging(l logging.Logger, level logging.Level) func(h *client) error {
+	return func(c *client) error {
+		c.logger = l
+		c.logLevel = level
+		return nil
+	}
+}
+
+// Cache sets the cache used to serve repeated read requests. Requests
+// that change the service's state invalidate the cached responses to
+// budget operations.
+func Cache(rc *cache.Cache) func(h *client) error {
+	return func(c *client) error {
+		c.cache = rc
+		return nil
+	}
+}
+
+// RateLimit sets the limiter that paces the requests sent by the
+// client. The same limiter may be shared with other clients.
+func RateLimit(l *ratelimit.Limiter) func(h *client) error {
+	return func(c *client) error {
+		c.limiter = l
+		return nil
+	}
+}
+
 // NewClient returns a new budget API client using the provided http client.
 func NewClient(options ...ClientOption) (*client, error) {
 	c := &client{
-		h:       httpbakery.NewClient(),
 		apiRoot: romulus.DefaultAPIRoot,
 	}
 
@@ -68,6 +178,16 @@ func NewClient(options ...ClientOption) (*client, error) {
 		}
 	}
 
+	if c.authenticator != nil {
+		h, err := auth.NewClient(c.authenticator, c.h)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		c.h = h
+	} else if c.h == nil {
+		c.h = httpbakery.NewClient()
+	}
+
 	return c, nil
 }
 
@@ -83,9 +203,37 @@ func (c *client) CreateWallet(name string, limit string) (string, error) {
 	return response, err
 }
 
-// ListWallets lists the wallets belonging to the current user.
+// ListWallets lists the wallets belonging to the current user. If the
+// service paginates the wallets, all pages are retrieved.
 func (c *client) ListWallets() (*wireformat.ListWalletsResponse, error) {
-	list := wireformat.ListWalletsRequest{}
+	response, err := c.ListWalletsPage("", 0)
+	if err != nil {
+		return nil, err
+	}
+	cursors := newCursorSet()
+	for response.Next != "" {
+		if err := cursors.add(response.Next); err != nil {
+			return nil, err
+		}
+		page, err := c.ListWalletsPage(response.Next, 0)
+		if err != nil {
+			return nil, err
+		}
+		response.Wallets = append(response.Wallets, page.Wallets...)
+		response.Next = page.Next
+	}
+	return response, nil
+}
+
+// ListWalletsPage returns a single page of at most limit wallets
+// belonging to the current user, starting at the given cursor. An
+// empty cursor requests the first page and a zero limit uses the
+// service default.
+func (c *client) ListWalletsPage(cursor string, limit int) (*wireformat.ListWalletsResponse, error) {
+	list := wireformat.ListWalletsRequest{
+		Cursor: cursor,
+		Limit:  limit,
+	}
 	var response wireformat.ListWalletsResponse
 	err := c.doRequest(list, &response)
 	if err != nil {
@@ -105,10 +253,37 @@ func (c *client) SetWallet(wallet, limit string) (string, error) {
 	return response, err
 }
 
-// GetWallet returns the information of a particular wallet.
+// GetWallet returns the information of a particular wallet. If the
+// service paginates the wallet's budgets, all pages are retrieved.
 func (c *client) GetWallet(wallet string) (*wireformat.WalletWithBudgets, error) {
+	response, err := c.GetWalletPage(wallet, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	cursors := newCursorSet()
+	for response.Next != "" {
+		if err := cursors.add(response.Next); err != nil {
+			return nil, err
+		}
+		page, err := c.GetWalletPage(wallet, response.Next, 0)
+		if err != nil {
+			return nil, err
+		}
+		response.Budgets = append(response.Budgets, page.Budgets...)
+		response.Next = page.Next
+	}
+	return response, nil
+}
+
+// GetWalletPage returns the information of a particular wallet with
+// a single page of at most limit of its budgets, starting at the given
+// cursor. An empty cursor requests the first page and a zero limit uses
+// the service default.
+func (c *client) GetWalletPage(wallet, cursor string, limit int) (*wireformat.WalletWithBudgets, error) {
 	get := wireformat.GetWalletRequest{
 		Wallet: wallet,
+		Cursor: cursor,
+		Limit:  limit,
 	}
 	var response wireformat.WalletWithBudgets
 	err := c.doRequest(get, &response)
@@ -118,27 +293,235 @@ func (c *client) GetWallet(wallet string) (*wireformat.WalletWithBudgets, error)
 	return &response, nil
 }
 
+// DeleteWallet deletes the specified wallet. Unless force is set, a
+// wallet that still has budgets is not deleted.
+func (c *client) DeleteWallet(wallet string, force bool) (string, error) {
+	if !force {
+		w, err := c.GetWallet(wallet)
+		if err != nil {
+			return "", err
+		}
+		if n := len(w.Budgets); n > 0 {
+			return "", errors.Errorf("wallet %q still has %d budget(s)", wallet, n)
+		}
+	}
+	del := wireformat.DeleteWalletRequest{
+		Wallet: wallet,
+		Force:  force,
+	}
+	var response string
+	err := c.doRequest(del, &response)
+	return response, err
+}
+
+// RenameWallet changes the name of the wallet.
+func (c *client) RenameWallet(wallet, name string) (string, error) {
+	rename := wireformat.RenameWalletRequest{
+		Wallet: wallet,
+		Name:   name,
+	}
+	var response string
+	err := c.doRequest(rename, &response)
+	return response, err
+}
+
+// SetDefaultWallet makes the wallet the user's default wallet.
+func (c *client) SetDefaultWallet(wallet string) (string, error) {
+	set := wireformat.SetDefaultWalletRequest{
+		Wallet:  wallet,
+		Default: true,
+	}
+	var response string
+	err := c.doRequest(set, &response)
+	return response, err
+}
+
+// GrantWallet grants a user or group the specified permission on
+// the wallet.
+func (c *client) GrantWallet(wallet, subject string, permission wireformat.Permission) (string, error) {
+	if err := permission.Validate(); err != nil {
+		return "", errors.Trace(err)
+	}
+	grant := wireformat.GrantWalletRequest{
+		Wallet:     wallet,
+		Subject:    subject,
+		Permission: permission,
+	}
+	var response string
+	err := c.doRequest(grant, &response)
+	return response, err
+}
+
+// RevokeWallet revokes the access to the wallet granted to a user
+// or group.
+func (c *client) RevokeWallet(wallet, subject string) (string, error) {
+	revoke := wireformat.RevokeWalletRequest{
+		Wallet:  wallet,
+		Subject: subject,
+	}
+	var response string
+	err := c.doRequest(revoke, &response)
+	return response, err
+}
+
+// ListWalletGrants lists the users and groups granted access to
+// the wallet.
+func (c *client) ListWalletGrants(wallet string) ([]wireformat.WalletGrant, error) {
+	list := wireformat.ListWalletGrantsRequest{
+		Wallet: wallet,
+	}
+	var response wireformat.ListWalletGrantsResponse
+	err := c.doRequest(list, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Grants, nil
+}
+
+// GetCredit returns the user's credit balance, where the credit came
+// from and how it has been applied across wallets.
+func (c *client) GetCredit() (*wireformat.CreditResponse, error) {
+	var response wireformat.CreditResponse
+	err := c.doRequest(wireformat.GetCreditRequest{}, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// TopUp records a top-up of the user's credit with the given payment
+// reference.
+func (c *client) TopUp(amount, reference string) (string, error) {
+	if err := validateCreditAmount(amount); err != nil {
+		return "", err
+	}
+	topUp := wireformat.TopUpRequest{
+		Amount:    amount,
+		Reference: reference,
+	}
+	var response string
+	err := c.doRequest(topUp, &response)
+	return response, err
+}
+
+// AllocateCredit applies the given amount of the user's unallocated
+// credit to the wallet.
+func (c *client) AllocateCredit(wallet, amount string) (string, error) {
+	if err := validateCreditAmount(amount); err != nil {
+		return "", err
+	}
+	allocate := wireformat.AllocateCreditRequest{
+		Wallet: wallet,
+		Amount: amount,
+	}
+	var response string
+	err := c.doRequest(allocate, &response)
+	return response, err
+}
+
+// validateCreditAmount checks that amount is a positive number.
+func validateCreditAmount(amount string) error {
+	value, err := parseAmount(amount)
+	if err != nil || value <= 0 {
+		return errors.NotValidf("credit amount %q", amount)
+	}
+	return nil
+}
+
+// parseAmount parses a monetary amount, rejecting the NaN and infinite
+// values accepted by strconv.ParseFloat.
+func parseAmount(amount string) (float64, error) {
+	value, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, errors.NotValidf("amount %q", amount)
+	}
+	return value, nil
+}
+
+// BudgetOption defines a function which sets an optional property of
+// a budget when it is created or updated.
+type BudgetOption func(*budgetOptions) error
+
+type budgetOptions struct {
+	enforcement wireformat.EnforcementPolicy
+}
+
+// Enforcement sets the action taken when the budget's consumption
+// reaches its limit.
+func Enforcement(policy wireformat.EnforcementPolicy) BudgetOption {
+	return func(o *budgetOptions) error {
+		if err := policy.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+		o.enforcement = policy
+		return nil
+	}
+}
+
+func newBudgetOptions(options []BudgetOption) (budgetOptions, error) {
+	var o budgetOptions
+	for _, option := range options {
+		if err := option(&o); err != nil {
+			return budgetOptions{}, err
+		}
+	}
+	return o, nil
+}
+
 // CreateBudget creates a new budget in a specific wallet.
-func (c *client) CreateBudget(wallet, limit string, model string) (string, error) {
+func (c *client) CreateBudget(wallet, limit string, model string, options ...BudgetOption) (string, error) {
+	o, err := newBudgetOptions(options)
+	if err != nil {
+		return "", err
+	}
 	create := wireformat.CreateBudgetRequest{
-		Wallet: wallet,
-		Limit:  limit,
-		Model:  model,
+		Wallet:      wallet,
+		Limit:       limit,
+		Model:       model,
+		Enforcement: o.enforcement,
 	}
 	var response string
-	err := c.doRequest(create, &response)
+	err = c.doRequest(create, &response)
 	return response, err
 }
 
 // UpdateBudget updates the budget associated with the specified model with new limit.
-func (c *client) UpdateBudget(model, wallet, limit string) (string, error) {
+func (c *client) UpdateBudget(model, wallet, limit string, options ...BudgetOption) (string, error) {
+	o, err := newBudgetOptions(options)
+	if err != nil {
+		return "", err
+	}
 	create := wireformat.UpdateBudgetRequest{
-		Limit:  limit,
-		Model:  model,
-		Wallet: wallet,
+		Limit:       limit,
+		Model:       model,
+		Wallet:      wallet,
+		Enforcement: o.enforcement,
 	}
 	var response string
-	err := c.doRequest(create, &response)
+	err = c.doRequest(create, &response)
+	return response, err
+}
+
+// SetBudgetAlerts sets the usage percentages at which alerts are raised
+// for the budget associated with the specified model.
+func (c *client) SetBudgetAlerts(model string, thresholds []int) (string, error) {
+	if len(thresholds) == 0 {
+		return "", errors.New("no alert thresholds specified")
+	}
+	for _, t := range thresholds {
+		if t <= 0 {
+			return "", errors.NotValidf("alert threshold %d%%", t)
+		}
+	}
+	update := wireformat.UpdateBudgetRequest{
+		Model:           model,
+		AlertThresholds: thresholds,
+	}
+	var response string
+	err := c.doRequest(update, &response)
 	return response, err
 }
 
@@ -152,6 +535,152 @@ func (c *client) DeleteBudget(model string) (string, error) {
 	return response, err
 }
 
+// MoveBudget moves the budget associated with the specified model to
+// another wallet, after checking that the destination wallet has enough
+// unallocated funds for the budget limit. It returns the state of the
+// source and destination wallets after the move. If the budget was moved
+// but the state of a wallet cannot be retrieved, the error says so and
+// the state of the source wallet is returned if it was retrieved.
+func (c *client) MoveBudget(model, wallet string) (*wireformat.WalletWithBudgets, *wireformat.WalletWithBudgets, error) {
+	b, err := c.GetBudget(model)
+	if err != nil {
+		return nil, nil, err
+	}
+	if b.Wallet == "" {
+		return nil, nil, errors.Errorf("cannot determine the wallet of the budget for model %q", model)
+	}
+	if b.Wallet == wallet {
+		return nil, nil, errors.Errorf("budget for model %q is already in wallet %q", model, wallet)
+	}
+	destination, err := c.GetWallet(wallet)
+	if err != nil {
+		return nil, nil, err
+	}
+	limit, err := parseAmount(b.Limit)
+	if err != nil {
+		return nil, nil, errors.Annotatef(err, "invalid limit for the budget of model %q", model)
+	}
+	unallocated, err := parseAmount(destination.Total.Unallocated)
+	if err != nil {
+		return nil, nil, errors.Annotatef(err, "invalid unallocated amount in wallet %q", wallet)
+	}
+	if limit > unallocated {
+		return nil, nil, errors.Errorf("budget limit %s exceeds the %s unallocated in wallet %q", b.Limit, destination.Total.Unallocated, wallet)
+	}
+
+	move := wireformat.UpdateBudgetRequest{
+		Model:  model,
+		Wallet: wallet,
+	}
+	err = c.doRequest(move, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	source, err := c.GetWallet(b.Wallet)
+	if err != nil {
+		return nil, nil, errors.Annotatef(err, "budget for model %q moved to wallet %q, but cannot get wallet %q", model, wallet, b.Wallet)
+	}
+	destination, err = c.GetWallet(wallet)
+	if err != nil {
+		return source, nil, errors.Annotatef(err, "budget for model %q moved to wallet %q, but cannot get wallet %q", model, wallet, wallet)
+	}
+	return source, destination, nil
+}
+
+// GetBudget returns the budget associated with the specified model.
+func (c *client) GetBudget(model string) (*wireformat.Budget, error) {
+	get := wireformat.GetBudgetRequest{
+		Model: model,
+	}
+	var response wireformat.Budget
+	err := c.doRequest(get, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListBudgets lists the budgets matching the filter.
+func (c *client) ListBudgets(filter wireformat.ListBudgetsRequest) ([]wireformat.Budget, error) {
+	var response wireformat.ListBudgetsResponse
+	err := c.doRequest(filter, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Budgets, nil
+}
+
+// Ledger returns a page of the ledger of a wallet or of the budget
+// associated with a model. The next page is requested by setting the
+// request's Cursor to the Next value of the response.
+func (c *client) Ledger(req wireformat.LedgerRequest) (*wireformat.LedgerResponse, error) {
+	if req.Wallet == "" && req.Model == "" {
+		return nil, errors.New("wallet or model must be specified")
+	}
+	if !req.From.IsZero() && !req.To.IsZero() && req.To.Before(req.From) {
+		return nil, errors.New("end of ledger range precedes its start")
+	}
+	var response wireformat.LedgerResponse
+	err := c.doRequest(req, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// send sends the request in a span with the given attributes, or
+// serves it from the cache, reporting it to the observer and logger if
+// they are set. Cache hits are reported to the observer separately
+// from the requests sent.
+func (c *client) send(operation string, req *http.Request, attrs ...attribute.KeyValue) (*http.Response, error) {
+	req, end := instrument.StartSpan(c.tracer, operation, req, attrs...)
+	sent := false
+	resp, err := c.cache.Do(operation, req, func(req *http.Request) (*http.Response, error) {
+		sent = true
+		start := time.Now()
+		resp, err := c.limiter.Do(req, func(req *http.Request) (*http.Response, error) {
+			return logging.Do(c.logger, c.logLevel, c.h, operation, req)
+		})
+		instrument.Observe(c.observer, operation, start, resp, err)
+		return resp, err
+	})
+	if req.Method != "GET" {
+		c.cache.Invalidate("budget.")
+	}
+	if sent {
+		end(resp, err)
+	} else {
+		instrument.CacheHit(c.observer, operation, req)
+		end(nil, nil)
+	}
+	return resp, err
+}
+
+// spanAttributes returns the span attributes for the wallet and model
+// named in the request, if any.
+func spanAttributes(req interface{}) []attribute.KeyValue {
+	v := reflect.ValueOf(req)
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	var attrs []attribute.KeyValue
+	if f := v.FieldByName("Wallet"); f.Kind() == reflect.String {
+		attrs = append(attrs, instrument.WalletKey.String(f.String()))
+	}
+	if f := v.FieldByName("Model"); f.Kind() == reflect.String {
+		attrs = append(attrs, instrument.ModelKey.String(f.String()))
+	}
+	return attrs
+}
+
+// operationName returns the name of the operation performed by the
+// request, which is derived from the name of its type, so that a
+// CreateWalletRequest is named "budget.CreateWallet".
+func operationName(req interface{}) string {
+	return "budget." + strings.TrimSuffix(reflect.TypeOf(req).Name(), "Request")
+}
+
 // hasURL is an interface implemented by request structures that
 // modify the request URL.
 type hasURL interface {
@@ -194,6 +723,8 @@ func (c *client) doRequest(req interface{}, result interface{}) error {
 		return errors.Trace(err)
 	}
 
+	operation := operationName(req)
+	attrs := spanAttributes(req)
 	method := "GET"
 	if methodP, ok := req.(hasMethod); ok {
 		method = methodP.Method()
@@ -215,7 +746,7 @@ func (c *client) doRequest(req interface{}, result interface{}) error {
 		if ctype, ok := req.(hasContentType); ok {
 			r.Header.Add("Content-Type", ctype.ContentType())
 		}
-		resp, err = c.h.Do(r)
+		resp, err = c.send(operation, r, attrs...)
 		if err != nil {
 			if strings.HasSuffix(err.Error(), "Connection refused") {
 				return common.NotAvailError{}
@@ -228,7 +759,7 @@ func (c *client) doRequest(req interface{}, result interface{}) error {
 		if err != nil {
 			return errors.Annotate(err, "failed to create request")
 		}
-		resp, err = c.h.Do(r)
+		resp, err = c.send(operation, r, attrs...)
 		if err != nil {
 			return errors.Annotate(err, "failed to execute request")
 		}
@@ -8,14 +8,22 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/juju/errors"
 	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/romulus/api/auth"
 	"github.com/juju/romulus/api/budget"
+	"github.com/juju/romulus/api/cache"
+	"github.com/juju/romulus/api/ratelimit"
 	wireformat "github.com/juju/romulus/wireformat/budget"
 	"github.com/juju/romulus/wireformat/common"
 )
@@ -84,6 +92,198 @@ func (t *TSuite) TestCreateWalletAPIRoot(c *gc.C) {
 			}}})
 }
 
+func (t *TSuite) TestCreateWalletAuthentication(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(httpErr{Error: "unauthorized"})
+			return
+		}
+		json.NewEncoder(w).Encode("Wallet created successfully")
+	}))
+	defer server.Close()
+
+	client, err := budget.NewClient(budget.APIRoot(server.URL), budget.Authentication(auth.BearerToken("secret")))
+	c.Assert(err, jc.ErrorIsNil)
+	response, err := client.CreateWallet("personal", "200")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response, gc.Equals, "Wallet created successfully")
+
+	client, err = budget.NewClient(budget.APIRoot(server.URL), budget.Authentication(auth.BearerToken("wrong")))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.CreateWallet("personal", "200")
+	c.Assert(err, gc.ErrorMatches, "unauthorized")
+}
+
+func (t *TSuite) TestInstrument(c *gc.C) {
+	respBody, err := json.Marshal("wallet created")
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBody: respBody,
+	}
+	observer := &mockObserver{}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient), budget.Instrument(observer))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.CreateWallet("personal", "100")
+	c.Assert(err, jc.ErrorIsNil)
+
+	httpClient.RespCode = http.StatusNotFound
+	_, err = client.GetBudget("model-uuid")
+	c.Assert(err, gc.NotNil)
+
+	httpClient.SetErrors(errors.New("bogus error"))
+	_, err = client.ListWallets()
+	c.Assert(err, gc.NotNil)
+
+	c.Assert(observer.requests, jc.DeepEquals, []string{
+		"budget.CreateWallet 200",
+		"budget.GetBudget 404",
+		"budget.ListWallets error",
+	})
+}
+
+func (t *TSuite) TestTracing(c *gc.C) {
+	var traceparents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		traceparents = append(traceparents, req.Header.Get("traceparent"))
+		if req.Method == "DELETE" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(httpErr{Error: "budget not found"})
+			return
+		}
+		json.NewEncoder(w).Encode("budget updated")
+	}))
+	defer server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	client, err := budget.NewClient(budget.APIRoot(server.URL), budget.HTTPClient(server.Client()), budget.TracerProvider(provider))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.UpdateBudget("model-uuid", "personal", "10")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.DeleteBudget("model-uuid")
+	c.Assert(err, gc.ErrorMatches, "budget not found")
+
+	spans := exporter.GetSpans()
+	c.Assert(spans, gc.HasLen, 2)
+	c.Assert(traceparents, gc.HasLen, 2)
+	for i, span := range spans {
+		// The service receives the trace and span ids of the client span.
+		c.Assert(traceparents[i], gc.Equals, "00-"+span.SpanContext.TraceID().String()+"-"+span.SpanContext.SpanID().String()+"-01")
+	}
+	c.Assert(spans[0].Name, gc.Equals, "budget.UpdateBudget")
+	c.Assert(spanAttributes(spans[0]), jc.DeepEquals, map[string]string{
+		"romulus.operation":  "budget.UpdateBudget",
+		"romulus.wallet":     "personal",
+		"romulus.model_uuid": "model-uuid",
+		"http.status_code":   "200",
+	})
+	c.Assert(spans[1].Name, gc.Equals, "budget.DeleteBudget")
+	c.Assert(spans[1].Status.Code, gc.Equals, codes.Error)
+	c.Assert(spanAttributes(spans[1]), jc.DeepEquals, map[string]string{
+		"romulus.operation":  "budget.DeleteBudget",
+		"romulus.model_uuid": "model-uuid",
+		"http.status_code":   "404",
+	})
+}
+
+func (t *TSuite) TestCache(c *gc.C) {
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBodies: marshalAll(c,
+			wireformat.WalletWithBudgets{Limit: "10"},
+			"budget updated",
+			wireformat.WalletWithBudgets{Limit: "20"},
+		),
+	}
+	rc, err := cache.New(cache.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+	client, err := budget.NewClient(budget.HTTPClient(httpClient), budget.Cache(rc))
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 0; i < 2; i++ {
+		wallet, err := client.GetWallet("personal")
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(wallet.Limit, gc.Equals, "10")
+	}
+	c.Assert(httpClient.Calls(), gc.HasLen, 1)
+
+	// Changing a budget invalidates the cached wallets.
+	_, err = client.UpdateBudget("model-uuid", "personal", "10")
+	c.Assert(err, jc.ErrorIsNil)
+	wallet, err := client.GetWallet("personal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(wallet.Limit, gc.Equals, "20")
+	c.Assert(httpClient.Calls(), gc.HasLen, 3)
+}
+
+func (t *TSuite) TestCacheInstrument(c *gc.C) {
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBody: marshalAll(c, wireformat.WalletWithBudgets{Limit: "10"})[0],
+	}
+	rc, err := cache.New(cache.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+	observer := &mockObserver{}
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	client, err := budget.NewClient(
+		budget.HTTPClient(httpClient),
+		budget.Cache(rc),
+		budget.Instrument(observer),
+		budget.TracerProvider(provider),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 0; i < 2; i++ {
+		_, err := client.GetWallet("personal")
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(httpClient.Calls(), gc.HasLen, 1)
+	// Only the request sent is observed; the cache hit is reported
+	// separately.
+	c.Assert(observer.requests, jc.DeepEquals, []string{"budget.GetWallet 200"})
+	c.Assert(observer.cacheHits, jc.DeepEquals, []string{"budget.GetWallet"})
+
+	spans := exporter.GetSpans()
+	c.Assert(spans, gc.HasLen, 2)
+	c.Assert(spanAttributes(spans[0]), jc.DeepEquals, map[string]string{
+		"romulus.operation": "budget.GetWallet",
+		"romulus.wallet":    "personal",
+		"http.status_code":  "200",
+	})
+	c.Assert(spanAttributes(spans[1]), jc.DeepEquals, map[string]string{
+		"romulus.operation": "budget.GetWallet",
+		"romulus.wallet":    "personal",
+		"romulus.cache":     "hit",
+	})
+}
+
+func (t *TSuite) TestRateLimitRetry(c *gc.C) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		data, err := ioutil.ReadAll(req.Body)
+		c.Check(err, jc.ErrorIsNil)
+		requests = append(requests, string(data))
+		if len(requests) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode("budget created")
+	}))
+	defer server.Close()
+
+	limiter, err := ratelimit.New(ratelimit.Config{Rate: 100, Burst: 10, MaxInFlight: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	client, err := budget.NewClient(budget.APIRoot(server.URL), budget.HTTPClient(server.Client()), budget.RateLimit(limiter))
+	c.Assert(err, jc.ErrorIsNil)
+	response, err := client.CreateBudget("personal", "10", "model-uuid")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response, gc.Equals, "budget created")
+	c.Assert(requests, gc.HasLen, 2)
+	c.Assert(requests[1], gc.Equals, requests[0])
+}
+
 func (t *TSuite) TestCreateWalletServerError(c *gc.C) {
 	respBody, err := json.Marshal(httpErr{Error: "wallet already exists"})
 	c.Assert(err, jc.ErrorIsNil)
@@ -241,6 +441,56 @@ func (t *TSuite) TestListWallets(c *gc.C) {
 			}}})
 }
 
+func (t *TSuite) TestListWalletsPaginated(c *gc.C) {
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBodies: marshalAll(c,
+			wireformat.ListWalletsResponse{
+				Wallets: wireformat.WalletSummaries{{Wallet: "personal"}},
+				Total:   wireformat.WalletTotals{Limit: "150"},
+				Next:    "page-2",
+			},
+			wireformat.ListWalletsResponse{
+				Wallets: wireformat.WalletSummaries{{Wallet: "work"}},
+			},
+		),
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	response, err := client.ListWallets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response, jc.DeepEquals, &wireformat.ListWalletsResponse{
+		Wallets: wireformat.WalletSummaries{{Wallet: "personal"}, {Wallet: "work"}},
+		Total:   wireformat.WalletTotals{Limit: "150"},
+	})
+	httpClient.CheckCalls(c,
+		[]jujutesting.StubCall{{
+			"Do",
+			[]interface{}{"GET",
+				"",
+				"https://api.jujucharms.com/omnibus/v3/wallet",
+				map[string]interface{}{},
+			}}, {
+			"Do",
+			[]interface{}{"GET",
+				"",
+				"https://api.jujucharms.com/omnibus/v3/wallet?cursor=page-2",
+				map[string]interface{}{},
+			}}})
+}
+
+func (t *TSuite) TestListWalletsRepeatedCursor(c *gc.C) {
+	page := wireformat.ListWalletsResponse{Next: "page-2"}
+	httpClient := &mockClient{
+		RespCode:   http.StatusOK,
+		RespBodies: marshalAll(c, page, page, page),
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.ListWallets()
+	c.Assert(err, gc.ErrorMatches, `pagination cursor "page-2" repeated`)
+}
+
 func (t *TSuite) TestListWalletsServerError(c *gc.C) {
 	respBody, err := json.Marshal(httpErr{Error: "wallet already exists"})
 	c.Assert(err, jc.ErrorIsNil)
@@ -406,6 +656,32 @@ func (t *TSuite) TestGetWallet(c *gc.C) {
 			}}})
 }
 
+func (t *TSuite) TestGetWalletPaginated(c *gc.C) {
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBodies: marshalAll(c,
+			wireformat.WalletWithBudgets{
+				Limit:   "4000.00",
+				Budgets: []wireformat.Budget{{Model: "model-a"}},
+				Next:    "page-2",
+			},
+			wireformat.WalletWithBudgets{
+				Limit:   "4000.00",
+				Budgets: []wireformat.Budget{{Model: "model-b"}},
+			},
+		),
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	response, err := client.GetWallet("personal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response, jc.DeepEquals, &wireformat.WalletWithBudgets{
+		Limit:   "4000.00",
+		Budgets: []wireformat.Budget{{Model: "model-a"}, {Model: "model-b"}},
+	})
+	c.Assert(httpClient.Calls()[1].Args[2], gc.Equals, "https://api.jujucharms.com/omnibus/v3/wallet/personal?cursor=page-2")
+}
+
 func (t *TSuite) TestGetWalletServerError(c *gc.C) {
 	respBody, err := json.Marshal(httpErr{Error: "wallet not found"})
 	c.Assert(err, jc.ErrorIsNil)
@@ -448,83 +724,85 @@ func (t *TSuite) TestGetWalletRequestError(c *gc.C) {
 			}}})
 }
 
-func (t *TSuite) TestCreateBudget(c *gc.C) {
-	expected := "Budget created successfully"
-	respBody, err := json.Marshal(expected)
+func (t *TSuite) TestDeleteWallet(c *gc.C) {
+	walletBody, err := json.Marshal(wireformat.WalletWithBudgets{Limit: "100"})
+	c.Assert(err, jc.ErrorIsNil)
+	respBody, err := json.Marshal("wallet deleted")
 	c.Assert(err, jc.ErrorIsNil)
 	httpClient := &mockClient{
-		RespCode: http.StatusOK,
-		RespBody: respBody,
+		RespCode:   http.StatusOK,
+		RespBodies: [][]byte{walletBody, respBody},
 	}
 	client, err := budget.NewClient(budget.HTTPClient(httpClient))
 	c.Assert(err, jc.ErrorIsNil)
-	response, err := client.CreateBudget("personal", "200", "model")
+	response, err := client.DeleteWallet("personal", false)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(response, gc.Equals, expected)
+	c.Assert(response, gc.Equals, "wallet deleted")
 	httpClient.CheckCalls(c,
 		[]jujutesting.StubCall{{
 			"Do",
-			[]interface{}{"POST",
-				"application/json",
-				"https://api.jujucharms.com/omnibus/v3/wallet/personal/budget",
-				map[string]interface{}{
-					"limit": "200",
-					"model": "model",
-				},
+			[]interface{}{"GET",
+				"",
+				"https://api.jujucharms.com/omnibus/v3/wallet/personal",
+				map[string]interface{}{},
+			}}, {
+			"Do",
+			[]interface{}{"DELETE",
+				"",
+				"https://api.jujucharms.com/omnibus/v3/wallet/personal",
+				map[string]interface{}{},
 			}}})
 }
 
-func (t *TSuite) TestCreateBudgetServerError(c *gc.C) {
-	respBody, err := json.Marshal(httpErr{Error: "cannot create budget"})
+func (t *TSuite) TestDeleteWalletWithBudgets(c *gc.C) {
+	walletBody, err := json.Marshal(wireformat.WalletWithBudgets{
+		Limit:   "100",
+		Budgets: []wireformat.Budget{{Model: "model.joe", Limit: "10"}},
+	})
 	c.Assert(err, jc.ErrorIsNil)
 	httpClient := &mockClient{
-		RespCode: http.StatusBadRequest,
-		RespBody: respBody,
+		RespCode: http.StatusOK,
+		RespBody: walletBody,
 	}
 	client, err := budget.NewClient(budget.HTTPClient(httpClient))
 	c.Assert(err, jc.ErrorIsNil)
-	response, err := client.CreateBudget("personal", "200", "model")
-	c.Assert(err, gc.ErrorMatches, "cannot create budget")
+	response, err := client.DeleteWallet("personal", false)
+	c.Assert(err, gc.ErrorMatches, `wallet "personal" still has 1 budget\(s\)`)
 	c.Assert(response, gc.Equals, "")
 	httpClient.CheckCalls(c,
 		[]jujutesting.StubCall{{
 			"Do",
-			[]interface{}{"POST",
-				"application/json",
-				"https://api.jujucharms.com/omnibus/v3/wallet/personal/budget",
-				map[string]interface{}{
-					"limit": "200",
-					"model": "model",
-				},
+			[]interface{}{"GET",
+				"",
+				"https://api.jujucharms.com/omnibus/v3/wallet/personal",
+				map[string]interface{}{},
 			}}})
 }
 
-func (t *TSuite) TestCreateBudgetRequestError(c *gc.C) {
+func (t *TSuite) TestDeleteWalletForce(c *gc.C) {
+	respBody, err := json.Marshal("wallet deleted")
+	c.Assert(err, jc.ErrorIsNil)
 	httpClient := &mockClient{
-		RespCode: http.StatusBadRequest,
+		RespCode: http.StatusOK,
+		RespBody: respBody,
 	}
-	httpClient.SetErrors(errors.New("bogus error"))
 	client, err := budget.NewClient(budget.HTTPClient(httpClient))
 	c.Assert(err, jc.ErrorIsNil)
-	response, err := client.CreateBudget("personal", "200", "model")
-	c.Assert(err, gc.ErrorMatches, ".*bogus error")
-	c.Assert(response, gc.Equals, "")
+	response, err := client.DeleteWallet("personal", true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response, gc.Equals, "wallet deleted")
 	httpClient.CheckCalls(c,
 		[]jujutesting.StubCall{{
 			"Do",
-			[]interface{}{"POST",
-				"application/json",
-				"https://api.jujucharms.com/omnibus/v3/wallet/personal/budget",
-				map[string]interface{}{
-					"limit": "200",
-					"model": "model",
-				},
+			[]interface{}{"DELETE",
+				"",
+				"https://api.jujucharms.com/omnibus/v3/wallet/personal?force=true",
+				map[string]interface{}{},
 			}}})
 }
 
-func (t *TSuite) TestUpdateBudget(c *gc.C) {
-	expected := "Budget updated."
-	respBody, err := json.Marshal(expected)
+func (t *TSuite) TestRenameWallet(c *gc.C) {
+	respBody, err := json.Marshal("wallet renamed")
 	c.Assert(err, jc.ErrorIsNil)
 	httpClient := &mockClient{
 		RespCode: http.StatusOK,
@@ -532,78 +810,87 @@ func (t *TSuite) TestUpdateBudget(c *gc.C) {
 	}
 	client, err := budget.NewClient(budget.HTTPClient(httpClient))
 	c.Assert(err, jc.ErrorIsNil)
-	response, err := client.UpdateBudget("model-uuid", "personal", "200")
+	response, err := client.RenameWallet("personal", "hobby")
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(response, gc.Equals, expected)
+	c.Assert(response, gc.Equals, "wallet renamed")
 	httpClient.CheckCalls(c,
 		[]jujutesting.StubCall{{
 			"Do",
 			[]interface{}{"PATCH",
 				"application/json",
-				"https://api.jujucharms.com/omnibus/v3/model/model-uuid/budget",
+				"https://api.jujucharms.com/omnibus/v3/wallet/personal",
 				map[string]interface{}{
 					"update": map[string]interface{}{
-						"limit":  "200",
-						"wallet": "personal",
+						"name": "hobby",
 					},
 				},
 			}}})
 }
 
-func (t *TSuite) TestUpdateBudgetServerError(c *gc.C) {
-	respBody, err := json.Marshal(httpErr{Error: "cannot update budget"})
+func (t *TSuite) TestSetDefaultWallet(c *gc.C) {
+	respBody, err := json.Marshal("default wallet set")
 	c.Assert(err, jc.ErrorIsNil)
 	httpClient := &mockClient{
-		RespCode: http.StatusBadRequest,
+		RespCode: http.StatusOK,
 		RespBody: respBody,
 	}
 	client, err := budget.NewClient(budget.HTTPClient(httpClient))
 	c.Assert(err, jc.ErrorIsNil)
-	response, err := client.UpdateBudget("model-uuid", "work", "200")
-	c.Assert(err, gc.ErrorMatches, "cannot update budget")
-	c.Assert(response, gc.Equals, "")
+	response, err := client.SetDefaultWallet("work")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response, gc.Equals, "default wallet set")
 	httpClient.CheckCalls(c,
 		[]jujutesting.StubCall{{
 			"Do",
 			[]interface{}{"PATCH",
 				"application/json",
-				"https://api.jujucharms.com/omnibus/v3/model/model-uuid/budget",
+				"https://api.jujucharms.com/omnibus/v3/wallet/work",
 				map[string]interface{}{
 					"update": map[string]interface{}{
-						"limit":  "200",
-						"wallet": "work",
+						"default": true,
 					},
 				},
 			}}})
 }
 
-func (t *TSuite) TestUpdateBudgetRequestError(c *gc.C) {
+func (t *TSuite) TestGrantWallet(c *gc.C) {
+	respBody, err := json.Marshal("access granted")
+	c.Assert(err, jc.ErrorIsNil)
 	httpClient := &mockClient{
-		RespCode: http.StatusBadRequest,
+		RespCode: http.StatusOK,
+		RespBody: respBody,
 	}
-	httpClient.SetErrors(errors.New("bogus error"))
 	client, err := budget.NewClient(budget.HTTPClient(httpClient))
 	c.Assert(err, jc.ErrorIsNil)
-	response, err := client.UpdateBudget("model-uuid", "", "200")
-	c.Assert(err, gc.ErrorMatches, ".*bogus error")
-	c.Assert(response, gc.Equals, "")
+	response, err := client.GrantWallet("team", "user.jess", wireformat.SpendPermission)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response, gc.Equals, "access granted")
 	httpClient.CheckCalls(c,
 		[]jujutesting.StubCall{{
 			"Do",
-			[]interface{}{"PATCH",
+			[]interface{}{"POST",
 				"application/json",
-				"https://api.jujucharms.com/omnibus/v3/model/model-uuid/budget",
+				"https://api.jujucharms.com/omnibus/v3/wallet/team/grant",
 				map[string]interface{}{
-					"update": map[string]interface{}{
-						"limit": "200",
-					},
+					"subject":    "user.jess",
+					"permission": "spend",
 				},
 			}}})
 }
 
-func (t *TSuite) TestDeleteBudget(c *gc.C) {
-	expected := "Budget deleted."
-	respBody, err := json.Marshal(expected)
+func (t *TSuite) TestGrantWalletInvalidPermission(c *gc.C) {
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.GrantWallet("team", "user.jess", wireformat.Permission("admin"))
+	c.Assert(err, gc.ErrorMatches, `permission "admin" not valid`)
+	httpClient.CheckNoCalls(c)
+}
+
+func (t *TSuite) TestRevokeWallet(c *gc.C) {
+	respBody, err := json.Marshal("access revoked")
 	c.Assert(err, jc.ErrorIsNil)
 	httpClient := &mockClient{
 		RespCode: http.StatusOK,
@@ -611,66 +898,809 @@ func (t *TSuite) TestDeleteBudget(c *gc.C) {
 	}
 	client, err := budget.NewClient(budget.HTTPClient(httpClient))
 	c.Assert(err, jc.ErrorIsNil)
-	response, err := client.DeleteBudget("model-uuid")
+	response, err := client.RevokeWallet("team", "user.jess")
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(response, gc.Equals, expected)
+	c.Assert(response, gc.Equals, "access revoked")
 	httpClient.CheckCalls(c,
 		[]jujutesting.StubCall{{
 			"Do",
 			[]interface{}{"DELETE",
 				"",
-				"https://api.jujucharms.com/omnibus/v3/model/model-uuid/budget",
+				"https://api.jujucharms.com/omnibus/v3/wallet/team/grant/user.jess",
 				map[string]interface{}{},
 			}}})
 }
 
-func (t *TSuite) TestDeleteBudgetServerError(c *gc.C) {
-	respBody, err := json.Marshal(httpErr{Error: "cannot delete budget"})
+func (t *TSuite) TestListWalletGrants(c *gc.C) {
+	expected := []wireformat.WalletGrant{{
+		Subject:    "user.jess",
+		Permission: wireformat.SpendPermission,
+	}, {
+		Subject:    "finance",
+		Permission: wireformat.ReadPermission,
+	}}
+	respBody, err := json.Marshal(wireformat.ListWalletGrantsResponse{Grants: expected})
 	c.Assert(err, jc.ErrorIsNil)
 	httpClient := &mockClient{
-		RespCode: http.StatusBadRequest,
+		RespCode: http.StatusOK,
 		RespBody: respBody,
 	}
 	client, err := budget.NewClient(budget.HTTPClient(httpClient))
 	c.Assert(err, jc.ErrorIsNil)
-	response, err := client.DeleteBudget("model-uuid")
-	c.Assert(err, gc.ErrorMatches, "cannot delete budget")
-	c.Assert(response, gc.Equals, "")
+	response, err := client.ListWalletGrants("team")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response, gc.DeepEquals, expected)
 	httpClient.CheckCalls(c,
 		[]jujutesting.StubCall{{
 			"Do",
-			[]interface{}{"DELETE",
+			[]interface{}{"GET",
 				"",
-				"https://api.jujucharms.com/omnibus/v3/model/model-uuid/budget",
+				"https://api.jujucharms.com/omnibus/v3/wallet/team/grant",
 				map[string]interface{}{},
 			}}})
 }
 
-func (t *TSuite) TestDeleteBudgetRequestError(c *gc.C) {
+func (t *TSuite) TestGetCredit(c *gc.C) {
+	expected := &wireformat.CreditResponse{
+		Balance:     "150.00",
+		Unallocated: "50.00",
+		Sources: []wireformat.CreditSource{{
+			Type:      wireformat.TopUpCredit,
+			Amount:    "100.00",
+			Remaining: "100.00",
+			Reference: "inv-1",
+			Time:      time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		}, {
+			Type:      wireformat.PromotionalCredit,
+			Amount:    "100.00",
+			Remaining: "50.00",
+			Time:      time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		}},
+		Allocations: []wireformat.CreditAllocation{{
+			Wallet:   "personal",
+			Amount:   "100.00",
+			Consumed: "50.00",
+		}},
+	}
+	respBody, err := json.Marshal(expected)
+	c.Assert(err, jc.ErrorIsNil)
 	httpClient := &mockClient{
-		RespCode: http.StatusBadRequest,
+		RespCode: http.StatusOK,
+		RespBody: respBody,
 	}
-	httpClient.SetErrors(errors.New("bogus error"))
 	client, err := budget.NewClient(budget.HTTPClient(httpClient))
 	c.Assert(err, jc.ErrorIsNil)
-	response, err := client.DeleteBudget("model-uuid")
-	c.Assert(err, gc.ErrorMatches, ".*bogus error")
-	c.Assert(response, gc.Equals, "")
+	response, err := client.GetCredit()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response, jc.DeepEquals, expected)
 	httpClient.CheckCalls(c,
 		[]jujutesting.StubCall{{
 			"Do",
-			[]interface{}{"DELETE",
+			[]interface{}{"GET",
 				"",
-				"https://api.jujucharms.com/omnibus/v3/model/model-uuid/budget",
+				"https://api.jujucharms.com/omnibus/v3/credit",
 				map[string]interface{}{},
 			}}})
 }
 
-type mockClient struct {
-	jujutesting.Stub
+func (t *TSuite) TestTopUp(c *gc.C) {
+	respBody, err := json.Marshal("credit added")
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBody: respBody,
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	response, err := client.TopUp("100.00", "inv-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response, gc.Equals, "credit added")
+	httpClient.CheckCalls(c,
+		[]jujutesting.StubCall{{
+			"Do",
+			[]interface{}{"POST",
+				"application/json",
+				"https://api.jujucharms.com/omnibus/v3/credit/top-up",
+				map[string]interface{}{
+					"amount":    "100.00",
+					"reference": "inv-1",
+				},
+			}}})
+}
 
-	RespCode int
+func (t *TSuite) TestAllocateCredit(c *gc.C) {
+	respBody, err := json.Marshal("credit allocated")
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBody: respBody,
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	response, err := client.AllocateCredit("personal", "25")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response, gc.Equals, "credit allocated")
+	httpClient.CheckCalls(c,
+		[]jujutesting.StubCall{{
+			"Do",
+			[]interface{}{"POST",
+				"application/json",
+				"https://api.jujucharms.com/omnibus/v3/wallet/personal/credit",
+				map[string]interface{}{
+					"amount": "25",
+				},
+			}}})
+}
+
+func (t *TSuite) TestInvalidCreditAmount(c *gc.C) {
+	httpClient := &mockClient{}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.TopUp("-5", "")
+	c.Assert(err, gc.ErrorMatches, `credit amount "-5" not valid`)
+	_, err = client.AllocateCredit("personal", "lots")
+	c.Assert(err, gc.ErrorMatches, `credit amount "lots" not valid`)
+	for _, amount := range []string{"NaN", "Inf", "+Inf", "-Inf", "1e400"} {
+		_, err = client.TopUp(amount, "")
+		c.Assert(err, gc.ErrorMatches, `credit amount ".*" not valid`, gc.Commentf("amount %q", amount))
+		_, err = client.AllocateCredit("personal", amount)
+		c.Assert(err, gc.ErrorMatches, `credit amount ".*" not valid`, gc.Commentf("amount %q", amount))
+	}
+	httpClient.CheckNoCalls(c)
+}
+
+func (t *TSuite) TestCreateBudget(c *gc.C) {
+	expected := "Budget created successfully"
+	respBody, err := json.Marshal(expected)
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBody: respBody,
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	response, err := client.CreateBudget("personal", "200", "model")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response, gc.Equals, expected)
+	httpClient.CheckCalls(c,
+		[]jujutesting.StubCall{{
+			"Do",
+			[]interface{}{"POST",
+				"application/json",
+				"https://api.jujucharms.com/omnibus/v3/wallet/personal/budget",
+				map[string]interface{}{
+					"limit": "200",
+					"model": "model",
+				},
+			}}})
+}
+
+func (t *TSuite) TestCreateBudgetWithEnforcement(c *gc.C) {
+	respBody, err := json.Marshal("Budget created successfully")
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBody: respBody,
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.CreateBudget("personal", "200", "model", budget.Enforcement(wireformat.BlockPolicy))
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient.CheckCalls(c,
+		[]jujutesting.StubCall{{
+			"Do",
+			[]interface{}{"POST",
+				"application/json",
+				"https://api.jujucharms.com/omnibus/v3/wallet/personal/budget",
+				map[string]interface{}{
+					"limit":       "200",
+					"model":       "model",
+					"enforcement": "block",
+				},
+			}}})
+}
+
+func (t *TSuite) TestCreateBudgetInvalidEnforcement(c *gc.C) {
+	httpClient := &mockClient{}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.CreateBudget("personal", "200", "model", budget.Enforcement("shutdown"))
+	c.Assert(err, gc.ErrorMatches, `enforcement policy "shutdown" not valid`)
+	httpClient.CheckNoCalls(c)
+}
+
+func (t *TSuite) TestCreateBudgetServerError(c *gc.C) {
+	respBody, err := json.Marshal(httpErr{Error: "cannot create budget"})
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode: http.StatusBadRequest,
+		RespBody: respBody,
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	response, err := client.CreateBudget("personal", "200", "model")
+	c.Assert(err, gc.ErrorMatches, "cannot create budget")
+	c.Assert(response, gc.Equals, "")
+	httpClient.CheckCalls(c,
+		[]jujutesting.StubCall{{
+			"Do",
+			[]interface{}{"POST",
+				"application/json",
+				"https://api.jujucharms.com/omnibus/v3/wallet/personal/budget",
+				map[string]interface{}{
+					"limit": "200",
+					"model": "model",
+				},
+			}}})
+}
+
+func (t *TSuite) TestCreateBudgetRequestError(c *gc.C) {
+	httpClient := &mockClient{
+		RespCode: http.StatusBadRequest,
+	}
+	httpClient.SetErrors(errors.New("bogus error"))
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	response, err := client.CreateBudget("personal", "200", "model")
+	c.Assert(err, gc.ErrorMatches, ".*bogus error")
+	c.Assert(response, gc.Equals, "")
+	httpClient.CheckCalls(c,
+		[]jujutesting.StubCall{{
+			"Do",
+			[]interface{}{"POST",
+				"application/json",
+				"https://api.jujucharms.com/omnibus/v3/wallet/personal/budget",
+				map[string]interface{}{
+					"limit": "200",
+					"model": "model",
+				},
+			}}})
+}
+
+func (t *TSuite) TestUpdateBudget(c *gc.C) {
+	expected := "Budget updated."
+	respBody, err := json.Marshal(expected)
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBody: respBody,
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	response, err := client.UpdateBudget("model-uuid", "personal", "200")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response, gc.Equals, expected)
+	httpClient.CheckCalls(c,
+		[]jujutesting.StubCall{{
+			"Do",
+			[]interface{}{"PATCH",
+				"application/json",
+				"https://api.jujucharms.com/omnibus/v3/model/model-uuid/budget",
+				map[string]interface{}{
+					"update": map[string]interface{}{
+						"limit":  "200",
+						"wallet": "personal",
+					},
+				},
+			}}})
+}
+
+func (t *TSuite) TestUpdateBudgetServerError(c *gc.C) {
+	respBody, err := json.Marshal(httpErr{Error: "cannot update budget"})
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode: http.StatusBadRequest,
+		RespBody: respBody,
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	response, err := client.UpdateBudget("model-uuid", "work", "200")
+	c.Assert(err, gc.ErrorMatches, "cannot update budget")
+	c.Assert(response, gc.Equals, "")
+	httpClient.CheckCalls(c,
+		[]jujutesting.StubCall{{
+			"Do",
+			[]interface{}{"PATCH",
+				"application/json",
+				"https://api.jujucharms.com/omnibus/v3/model/model-uuid/budget",
+				map[string]interface{}{
+					"update": map[string]interface{}{
+						"limit":  "200",
+						"wallet": "work",
+					},
+				},
+			}}})
+}
+
+func (t *TSuite) TestUpdateBudgetRequestError(c *gc.C) {
+	httpClient := &mockClient{
+		RespCode: http.StatusBadRequest,
+	}
+	httpClient.SetErrors(errors.New("bogus error"))
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	response, err := client.UpdateBudget("model-uuid", "", "200")
+	c.Assert(err, gc.ErrorMatches, ".*bogus error")
+	c.Assert(response, gc.Equals, "")
+	httpClient.CheckCalls(c,
+		[]jujutesting.StubCall{{
+			"Do",
+			[]interface{}{"PATCH",
+				"application/json",
+				"https://api.jujucharms.com/omnibus/v3/model/model-uuid/budget",
+				map[string]interface{}{
+					"update": map[string]interface{}{
+						"limit": "200",
+					},
+				},
+			}}})
+}
+
+func (t *TSuite) TestUpdateBudgetEnforcement(c *gc.C) {
+	respBody, err := json.Marshal("Budget updated.")
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBody: respBody,
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.UpdateBudget("model-uuid", "", "", budget.Enforcement(wireformat.DegradePolicy))
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient.CheckCalls(c,
+		[]jujutesting.StubCall{{
+			"Do",
+			[]interface{}{"PATCH",
+				"application/json",
+				"https://api.jujucharms.com/omnibus/v3/model/model-uuid/budget",
+				map[string]interface{}{
+					"update": map[string]interface{}{
+						"enforcement": "degrade",
+					},
+				},
+			}}})
+}
+
+func (t *TSuite) TestSetBudgetAlerts(c *gc.C) {
+	respBody, err := json.Marshal("budget updated")
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBody: respBody,
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	response, err := client.SetBudgetAlerts("model-uuid", []int{50, 80, 100})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response, gc.Equals, "budget updated")
+	httpClient.CheckCalls(c,
+		[]jujutesting.StubCall{{
+			"Do",
+			[]interface{}{"PATCH",
+				"application/json",
+				"https://api.jujucharms.com/omnibus/v3/model/model-uuid/budget",
+				map[string]interface{}{
+					"update": map[string]interface{}{
+						"alert-thresholds": []interface{}{50.0, 80.0, 100.0},
+					},
+				},
+			}}})
+
+	_, err = client.SetBudgetAlerts("model-uuid", []int{50, 0})
+	c.Assert(err, gc.ErrorMatches, "alert threshold 0% not valid")
+	_, err = client.SetBudgetAlerts("model-uuid", nil)
+	c.Assert(err, gc.ErrorMatches, "no alert thresholds specified")
+}
+
+func (t *TSuite) TestDeleteBudget(c *gc.C) {
+	expected := "Budget deleted."
+	respBody, err := json.Marshal(expected)
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBody: respBody,
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	response, err := client.DeleteBudget("model-uuid")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response, gc.Equals, expected)
+	httpClient.CheckCalls(c,
+		[]jujutesting.StubCall{{
+			"Do",
+			[]interface{}{"DELETE",
+				"",
+				"https://api.jujucharms.com/omnibus/v3/model/model-uuid/budget",
+				map[string]interface{}{},
+			}}})
+}
+
+func (t *TSuite) TestDeleteBudgetServerError(c *gc.C) {
+	respBody, err := json.Marshal(httpErr{Error: "cannot delete budget"})
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode: http.StatusBadRequest,
+		RespBody: respBody,
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	response, err := client.DeleteBudget("model-uuid")
+	c.Assert(err, gc.ErrorMatches, "cannot delete budget")
+	c.Assert(response, gc.Equals, "")
+	httpClient.CheckCalls(c,
+		[]jujutesting.StubCall{{
+			"Do",
+			[]interface{}{"DELETE",
+				"",
+				"https://api.jujucharms.com/omnibus/v3/model/model-uuid/budget",
+				map[string]interface{}{},
+			}}})
+}
+
+func (t *TSuite) TestDeleteBudgetRequestError(c *gc.C) {
+	httpClient := &mockClient{
+		RespCode: http.StatusBadRequest,
+	}
+	httpClient.SetErrors(errors.New("bogus error"))
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	response, err := client.DeleteBudget("model-uuid")
+	c.Assert(err, gc.ErrorMatches, ".*bogus error")
+	c.Assert(response, gc.Equals, "")
+	httpClient.CheckCalls(c,
+		[]jujutesting.StubCall{{
+			"Do",
+			[]interface{}{"DELETE",
+				"",
+				"https://api.jujucharms.com/omnibus/v3/model/model-uuid/budget",
+				map[string]interface{}{},
+			}}})
+}
+
+func (t *TSuite) TestGetBudget(c *gc.C) {
+	expected := &wireformat.Budget{
+		Owner:       "user.joe",
+		Limit:       "1200.00",
+		Consumed:    "500.00",
+		Usage:       "42%",
+		Model:       "model-uuid",
+		Wallet:      "personal",
+		Enforcement: wireformat.BlockPolicy,
+	}
+	respBody, err := json.Marshal(expected)
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBody: respBody,
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	response, err := client.GetBudget("model-uuid")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response, gc.DeepEquals, expected)
+	httpClient.CheckCalls(c,
+		[]jujutesting.StubCall{{
+			"Do",
+			[]interface{}{"GET",
+				"",
+				"https://api.jujucharms.com/omnibus/v3/model/model-uuid/budget",
+				map[string]interface{}{},
+			}}})
+}
+
+func (t *TSuite) TestGetBudgetServerError(c *gc.C) {
+	respBody, err := json.Marshal(httpErr{Error: "budget not found"})
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode: http.StatusNotFound,
+		RespBody: respBody,
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	response, err := client.GetBudget("model-uuid")
+	c.Assert(err, gc.ErrorMatches, "budget not found")
+	c.Assert(response, gc.IsNil)
+}
+
+func (t *TSuite) TestListBudgets(c *gc.C) {
+	expected := []wireformat.Budget{{
+		Owner:    "user.joe",
+		Limit:    "10.00",
+		Consumed: "12.00",
+		Usage:    "120%",
+		Model:    "model.joe",
+		Wallet:   "personal",
+	}}
+	respBody, err := json.Marshal(wireformat.ListBudgetsResponse{Budgets: expected})
+	c.Assert(err, jc.ErrorIsNil)
+
+	tests := []struct {
+		about  string
+		filter wireformat.ListBudgetsRequest
+		url    string
+	}{{
+		about: "no filter",
+		url:   "https://api.jujucharms.com/omnibus/v3/budget",
+	}, {
+		about: "all filters",
+		filter: wireformat.ListBudgetsRequest{
+			Owner:     "user.joe",
+			Wallet:    "personal",
+			OverLimit: true,
+		},
+		url: "https://api.jujucharms.com/omnibus/v3/budget?over-limit=true&owner=user.joe&wallet=personal",
+	}}
+	for i, test := range tests {
+		c.Logf("test %d: %s", i, test.about)
+		httpClient := &mockClient{
+			RespCode: http.StatusOK,
+			RespBody: respBody,
+		}
+		client, err := budget.NewClient(budget.HTTPClient(httpClient))
+		c.Assert(err, jc.ErrorIsNil)
+		response, err := client.ListBudgets(test.filter)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(response, gc.DeepEquals, expected)
+		httpClient.CheckCalls(c,
+			[]jujutesting.StubCall{{
+				"Do",
+				[]interface{}{"GET",
+					"",
+					test.url,
+					map[string]interface{}{},
+				}}})
+	}
+}
+
+func (t *TSuite) TestMoveBudget(c *gc.C) {
+	budgetBody, err := json.Marshal(wireformat.Budget{Model: "model-uuid", Limit: "10", Wallet: "personal"})
+	c.Assert(err, jc.ErrorIsNil)
+	before, err := json.Marshal(wireformat.WalletWithBudgets{
+		Limit: "100",
+		Total: wireformat.WalletTotals{Unallocated: "40"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	moved, err := json.Marshal("budget updated")
+	c.Assert(err, jc.ErrorIsNil)
+	expectedSource := wireformat.WalletWithBudgets{
+		Limit: "50",
+		Total: wireformat.WalletTotals{Unallocated: "50"},
+	}
+	source, err := json.Marshal(expectedSource)
+	c.Assert(err, jc.ErrorIsNil)
+	expectedDestination := wireformat.WalletWithBudgets{
+		Limit:   "100",
+		Total:   wireformat.WalletTotals{Unallocated: "30"},
+		Budgets: []wireformat.Budget{{Model: "model-uuid", Limit: "10", Wallet: "work"}},
+	}
+	destination, err := json.Marshal(expectedDestination)
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode:   http.StatusOK,
+		RespBodies: [][]byte{budgetBody, before, moved, source, destination},
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	src, dst, err := client.MoveBudget("model-uuid", "work")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*src, jc.DeepEquals, expectedSource)
+	c.Assert(*dst, jc.DeepEquals, expectedDestination)
+	httpClient.CheckCalls(c,
+		[]jujutesting.StubCall{{
+			"Do",
+			[]interface{}{"GET",
+				"",
+				"https://api.jujucharms.com/omnibus/v3/model/model-uuid/budget",
+				map[string]interface{}{},
+			}}, {
+			"Do",
+			[]interface{}{"GET",
+				"",
+				"https://api.jujucharms.com/omnibus/v3/wallet/work",
+				map[string]interface{}{},
+			}}, {
+			"Do",
+			[]interface{}{"PATCH",
+				"application/json",
+				"https://api.jujucharms.com/omnibus/v3/model/model-uuid/budget",
+				map[string]interface{}{
+					"update": map[string]interface{}{
+						"wallet": "work",
+					},
+				},
+			}}, {
+			"Do",
+			[]interface{}{"GET",
+				"",
+				"https://api.jujucharms.com/omnibus/v3/wallet/personal",
+				map[string]interface{}{},
+			}}, {
+			"Do",
+			[]interface{}{"GET",
+				"",
+				"https://api.jujucharms.com/omnibus/v3/wallet/work",
+				map[string]interface{}{},
+			}}})
+}
+
+func (t *TSuite) TestMoveBudgetWalletError(c *gc.C) {
+	budgetBody, err := json.Marshal(wireformat.Budget{Model: "model-uuid", Limit: "10", Wallet: "personal"})
+	c.Assert(err, jc.ErrorIsNil)
+	walletBody, err := json.Marshal(wireformat.WalletWithBudgets{
+		Limit: "100",
+		Total: wireformat.WalletTotals{Unallocated: "40"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	moved, err := json.Marshal("budget updated")
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode:   http.StatusOK,
+		RespBodies: [][]byte{budgetBody, walletBody, moved, walletBody},
+	}
+	httpClient.SetErrors(nil, nil, nil, nil, errors.New("connection reset"))
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	src, dst, err := client.MoveBudget("model-uuid", "work")
+	c.Assert(err, gc.ErrorMatches, `budget for model "model-uuid" moved to wallet "work", but cannot get wallet "work": .*connection reset`)
+	c.Assert(src, gc.NotNil)
+	c.Assert(dst, gc.IsNil)
+	c.Assert(httpClient.Calls(), gc.HasLen, 5)
+
+	httpClient = &mockClient{
+		RespCode:   http.StatusOK,
+		RespBodies: [][]byte{budgetBody, walletBody, moved},
+	}
+	httpClient.SetErrors(nil, nil, nil, errors.New("connection reset"))
+	client, err = budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	src, dst, err = client.MoveBudget("model-uuid", "work")
+	c.Assert(err, gc.ErrorMatches, `budget for model "model-uuid" moved to wallet "work", but cannot get wallet "personal": .*connection reset`)
+	c.Assert(src, gc.IsNil)
+	c.Assert(dst, gc.IsNil)
+}
+
+func (t *TSuite) TestMoveBudgetInsufficientFunds(c *gc.C) {
+	budgetBody, err := json.Marshal(wireformat.Budget{Model: "model-uuid", Limit: "50", Wallet: "personal"})
+	c.Assert(err, jc.ErrorIsNil)
+	walletBody, err := json.Marshal(wireformat.WalletWithBudgets{
+		Limit: "100",
+		Total: wireformat.WalletTotals{Unallocated: "40"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode:   http.StatusOK,
+		RespBodies: [][]byte{budgetBody, walletBody},
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = client.MoveBudget("model-uuid", "work")
+	c.Assert(err, gc.ErrorMatches, `budget limit 50 exceeds the 40 unallocated in wallet "work"`)
+	c.Assert(httpClient.Calls(), gc.HasLen, 2)
+}
+
+func (t *TSuite) TestMoveBudgetNonFiniteAmount(c *gc.C) {
+	budgetBody, err := json.Marshal(wireformat.Budget{Model: "model-uuid", Limit: "50", Wallet: "personal"})
+	c.Assert(err, jc.ErrorIsNil)
+	walletBody, err := json.Marshal(wireformat.WalletWithBudgets{
+		Limit: "100",
+		Total: wireformat.WalletTotals{Unallocated: "NaN"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode:   http.StatusOK,
+		RespBodies: [][]byte{budgetBody, walletBody},
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = client.MoveBudget("model-uuid", "work")
+	c.Assert(err, gc.ErrorMatches, `invalid unallocated amount in wallet "work": amount "NaN" not valid`)
+	c.Assert(httpClient.Calls(), gc.HasLen, 2)
+}
+
+func (t *TSuite) TestMoveBudgetSameWallet(c *gc.C) {
+	budgetBody, err := json.Marshal(wireformat.Budget{Model: "model-uuid", Limit: "50", Wallet: "work"})
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBody: budgetBody,
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = client.MoveBudget("model-uuid", "work")
+	c.Assert(err, gc.ErrorMatches, `budget for model "model-uuid" is already in wallet "work"`)
+	c.Assert(httpClient.Calls(), gc.HasLen, 1)
+}
+
+func (t *TSuite) TestLedger(c *gc.C) {
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	expected := &wireformat.LedgerResponse{
+		Entries: []wireformat.LedgerEntry{{
+			Time:    time.Date(2026, 9, 3, 12, 0, 0, 0, time.UTC),
+			Type:    wireformat.LimitChangeEntry,
+			Wallet:  "personal",
+			Model:   "model-uuid",
+			Amount:  "20.00",
+			Balance: "20.00",
+		}, {
+			Time:    time.Date(2026, 9, 4, 12, 0, 0, 0, time.UTC),
+			Type:    wireformat.DebitEntry,
+			Wallet:  "personal",
+			Model:   "model-uuid",
+			Amount:  "25.00",
+			Balance: "-5.00",
+		}},
+		Next: "cursor-2",
+	}
+	respBody, err := json.Marshal(expected)
+	c.Assert(err, jc.ErrorIsNil)
+
+	tests := []struct {
+		about   string
+		request wireformat.LedgerRequest
+		url     string
+	}{{
+		about:   "wallet ledger",
+		request: wireformat.LedgerRequest{Wallet: "personal"},
+		url:     "https://api.jujucharms.com/omnibus/v3/wallet/personal/ledger",
+	}, {
+		about: "model ledger with range and paging",
+		request: wireformat.LedgerRequest{
+			Model:  "model-uuid",
+			From:   from,
+			To:     to,
+			Cursor: "cursor-1",
+			Limit:  2,
+		},
+		url: "https://api.jujucharms.com/omnibus/v3/model/model-uuid/ledger?cursor=cursor-1&from=2026-09-01T00%3A00%3A00Z&limit=2&to=2026-10-01T00%3A00%3A00Z",
+	}}
+	for i, test := range tests {
+		c.Logf("test %d: %s", i, test.about)
+		httpClient := &mockClient{
+			RespCode: http.StatusOK,
+			RespBody: respBody,
+		}
+		client, err := budget.NewClient(budget.HTTPClient(httpClient))
+		c.Assert(err, jc.ErrorIsNil)
+		response, err := client.Ledger(test.request)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(response, jc.DeepEquals, expected)
+		httpClient.CheckCalls(c,
+			[]jujutesting.StubCall{{
+				"Do",
+				[]interface{}{"GET",
+					"",
+					test.url,
+					map[string]interface{}{},
+				}}})
+	}
+}
+
+func (t *TSuite) TestLedgerInvalidRequest(c *gc.C) {
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.Ledger(wireformat.LedgerRequest{})
+	c.Assert(err, gc.ErrorMatches, "wallet or model must be specified")
+	_, err = client.Ledger(wireformat.LedgerRequest{
+		Wallet: "personal",
+		From:   time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+	})
+	c.Assert(err, gc.ErrorMatches, "end of ledger range precedes its start")
+	httpClient.CheckNoCalls(c)
+}
+
+type mockClient struct {
+	jujutesting.Stub
+
+	RespCode int
 	RespBody []byte
+	// RespBodies, if set, holds the response bodies returned
+	// by successive calls, in order.
+	RespBodies [][]byte
 }
 
 func (c *mockClient) Do(req *http.Request) (*http.Response, error) {
@@ -688,9 +1718,35 @@ func (c *mockClient) Do(req *http.Request) (*http.Response, error) {
 	}
 	c.Stub.MethodCall(c, "Do", req.Method, req.Header.Get("Content-Type"), req.URL.String(), requestData)
 
+	respBody := c.RespBody
+	if len(c.RespBodies) > 0 {
+		respBody, c.RespBodies = c.RespBodies[0], c.RespBodies[1:]
+	}
 	resp := &http.Response{
 		StatusCode: c.RespCode,
-		Body:       ioutil.NopCloser(bytes.NewReader(c.RespBody)),
+		Body:       ioutil.NopCloser(bytes.NewReader(respBody)),
 	}
 	return resp, c.Stub.NextErr()
 }
+
+type mockObserver struct {
+	requests  []string
+	cacheHits []string
+}
+
+func (o *mockObserver) ObserveRequest(operation, status string, duration time.Duration) {
+	o.requests = append(o.requests, operation+" "+status)
+}
+
+func (o *mockObserver) ObserveCacheHit(operation string) {
+	o.cacheHits = append(o.cacheHits, operation)
+}
+
+// spanAttributes returns the attributes of the span keyed by name.
+func spanAttributes(span tracetest.SpanStub) map[string]string {
+	attrs := make(map[string]string)
+	for _, attr := range span.Attributes {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	return attrs
+}
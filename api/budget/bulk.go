@@ -0,0 +1,239 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package budget
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+
+	wireformat "github.com/juju/romulus/wireformat/budget"
+)
+
+// DefaultBulkParallelism holds the number of bulk operations applied
+// at once by default.
+const DefaultBulkParallelism = 4
+
+// BulkAction is the action applied to a budget by a bulk operation.
+type BulkAction string
+
+const (
+	// BulkCreate creates a budget for the model.
+	BulkCreate BulkAction = "create"
+	// BulkUpdate updates the budget of the model.
+	BulkUpdate BulkAction = "update"
+	// BulkDelete deletes the budget of the model.
+	BulkDelete BulkAction = "delete"
+)
+
+// BulkOperation describes an operation applied to the budget of a
+// model by Bulk.
+type BulkOperation struct {
+	Action BulkAction
+	Model  string
+	// Wallet and Limit are used by create and update operations.
+	Wallet  string
+	Limit   string
+	Options []BudgetOption
+}
+
+// Validate checks the BulkOperation for errors.
+func (op BulkOperation) Validate() error {
+	if op.Model == "" {
+		return errors.NotValidf("empty model")
+	}
+	switch op.Action {
+	case BulkCreate:
+		if op.Wallet == "" || op.Limit == "" {
+			return errors.NotValidf("create without wallet and limit")
+		}
+	case BulkUpdate, BulkDelete:
+	default:
+		return errors.NotValidf("bulk action %q", op.Action)
+	}
+	return nil
+}
+
+// FailurePolicy determines what Bulk does when an operation fails.
+type FailurePolicy int
+
+const (
+	// ContinueOnFailure applies all the operations regardless of
+	// failures.
+	ContinueOnFailure FailurePolicy = iota
+	// StopOnFailure skips the operations not yet started once an
+	// operation fails.
+	StopOnFailure
+	// RollbackOnFailure skips the operations not yet started once an
+	// operation fails, and undoes the operations that succeeded.
+	RollbackOnFailure
+)
+
+// BulkConfig holds the configuration of a call to Bulk.
+type BulkConfig struct {
+	// Parallelism holds the number of operations applied at once. It
+	// defaults to DefaultBulkParallelism.
+	Parallelism int
+	// OnFailure determines what happens when an operation fails.
+	OnFailure FailurePolicy
+}
+
+// BulkResult holds the outcome of a bulk operation.
+type BulkResult struct {
+	Operation BulkOperation
+	// Response holds the service's response message if the operation
+	// succeeded.
+	Response string
+	// Err holds the error that caused the operation to fail. Errors
+	// returned by the service are common.HTTPError or
+	// common.NotAvailError values, and operations that were not
+	// attempted fail with ErrSkipped.
+	Err error
+	// RolledBack is set if the operation succeeded but was undone
+	// because another operation failed.
+	RolledBack bool
+	// RollbackErr holds the error that prevented the operation from
+	// being undone.
+	RollbackErr error
+}
+
+// ErrSkipped is the error of bulk operations that were not attempted
+// because an earlier operation failed.
+var ErrSkipped = errors.New("skipped after an earlier operation failed")
+
+// Bulk applies the operations concurrently, returning a result for
+// each in the same order. If any operation fails, an error is
+// returned along with the results.
+func (c *client) Bulk(ops []BulkOperation, config BulkConfig) ([]BulkResult, error) {
+	parallelism := config.Parallelism
+	if parallelism <= 0 {
+		parallelism = DefaultBulkParallelism
+	}
+	results := make([]BulkResult, len(ops))
+	// previous holds the budgets replaced or deleted by each
+	// operation, so that they can be restored on rollback.
+	previous := make([]*wireformat.Budget, len(ops))
+
+	var (
+		mu     sync.Mutex
+		failed bool
+		wg     sync.WaitGroup
+	)
+	slots := make(chan struct{}, parallelism)
+	for i, op := range ops {
+		results[i].Operation = op
+		slots <- struct{}{}
+		mu.Lock()
+		stop := failed && config.OnFailure != ContinueOnFailure
+		mu.Unlock()
+		if stop {
+			<-slots
+			results[i].Err = ErrSkipped
+			continue
+		}
+		wg.Add(1)
+		go func(i int, op BulkOperation) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			response, prev, err := c.applyBulk(op, config.OnFailure == RollbackOnFailure)
+			results[i].Response = response
+			results[i].Err = err
+			previous[i] = prev
+			if err != nil {
+				mu.Lock()
+				failed = true
+				mu.Unlock()
+			}
+		}(i, op)
+	}
+	wg.Wait()
+
+	if !failed {
+		return results, nil
+	}
+	if config.OnFailure == RollbackOnFailure {
+		for i := range results {
+			if results[i].Err != nil {
+				continue
+			}
+			results[i].RollbackErr = c.rollbackBulk(results[i].Operation, previous[i])
+			results[i].RolledBack = results[i].RollbackErr == nil
+		}
+	}
+	n := 0
+	for _, r := range results {
+		if r.Err != nil {
+			n++
+		}
+	}
+	return results, errors.Errorf("%d of %d budget operations failed", n, len(ops))
+}
+
+// applyBulk applies the operation. If keepPrevious is true the budget
+// replaced or deleted by the operation is returned.
+func (c *client) applyBulk(op BulkOperation, keepPrevious bool) (string, *wireformat.Budget, error) {
+	if err := op.Validate(); err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	var prev *wireformat.Budget
+	if keepPrevious && op.Action != BulkCreate {
+		var err error
+		prev, err = c.GetBudget(op.Model)
+		if err != nil {
+			return "", nil, errors.Annotatef(err, "cannot get budget for model %q", op.Model)
+		}
+		// Older services do not report the wallet of a budget, without
+		// which it cannot be restored.
+		if prev.Wallet == "" {
+			return "", nil, errors.Errorf("cannot determine the wallet of the budget for model %q", op.Model)
+		}
+	}
+	var response string
+	var err error
+	switch op.Action {
+	case BulkCreate:
+		response, err = c.CreateBudget(op.Wallet, op.Limit, op.Model, op.Options...)
+	case BulkUpdate:
+		response, err = c.UpdateBudget(op.Model, op.Wallet, op.Limit, op.Options...)
+	case BulkDelete:
+		response, err = c.DeleteBudget(op.Model)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return response, prev, nil
+}
+
+// rollbackBulk undoes the successful operation, restoring the budget
+// it replaced or deleted.
+func (c *client) rollbackBulk(op BulkOperation, prev *wireformat.Budget) error {
+	var err error
+	switch op.Action {
+	case BulkCreate:
+		_, err = c.DeleteBudget(op.Model)
+	case BulkUpdate:
+		// A policy cannot be cleared by an update, so a budget that
+		// had none is given NotifyPolicy, which has the same effect.
+		enforcement := prev.Enforcement
+		if enforcement == "" {
+			enforcement = wireformat.NotifyPolicy
+		}
+		_, err = c.UpdateBudget(op.Model, prev.Wallet, prev.Limit, Enforcement(enforcement))
+	case BulkDelete:
+		var options []BudgetOption
+		if prev.Enforcement != "" {
+			options = append(options, Enforcement(prev.Enforcement))
+		}
+		_, err = c.CreateBudget(prev.Wallet, prev.Limit, op.Model, options...)
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot roll back %s of budget for model %q", op.Action, op.Model)
+	}
+	if op.Action != BulkCreate && len(prev.AlertThresholds) > 0 {
+		if _, err := c.SetBudgetAlerts(op.Model, prev.AlertThresholds); err != nil {
+			return errors.Annotatef(err, "cannot restore alerts of budget for model %q", op.Model)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,324 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package budget_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/romulus/api/budget"
+	wireformat "github.com/juju/romulus/wireformat/budget"
+	"github.com/juju/romulus/wireformat/common"
+)
+
+type bulkSuite struct {
+	service *budgetService
+	server  *httptest.Server
+	client  budget.Client
+}
+
+var _ = gc.Suite(&bulkSuite{})
+
+func (s *bulkSuite) SetUpTest(c *gc.C) {
+	s.service = &budgetService{budgets: make(map[string]wireformat.Budget)}
+	s.server = httptest.NewServer(s.service)
+	var err error
+	s.client, err = budget.NewClient(budget.APIRoot(s.server.URL), budget.HTTPClient(s.server.Client()))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *bulkSuite) TearDownTest(c *gc.C) {
+	s.server.Close()
+}
+
+func (s *bulkSuite) TestBulk(c *gc.C) {
+	s.service.budgets["model-2"] = wireformat.Budget{Model: "model-2", Wallet: "personal", Limit: "5"}
+	s.service.budgets["model-3"] = wireformat.Budget{Model: "model-3", Wallet: "personal", Limit: "5"}
+	results, err := s.client.Bulk([]budget.BulkOperation{
+		{Action: budget.BulkCreate, Model: "model-1", Wallet: "personal", Limit: "10"},
+		{Action: budget.BulkUpdate, Model: "model-2", Limit: "20"},
+		{Action: budget.BulkDelete, Model: "model-3"},
+	}, budget.BulkConfig{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	for _, r := range results {
+		c.Assert(r.Err, jc.ErrorIsNil)
+		c.Assert(r.Response, gc.Equals, string(r.Operation.Action)+"d "+r.Operation.Model)
+	}
+	c.Assert(s.service.budgets, jc.DeepEquals, map[string]wireformat.Budget{
+		"model-1": {Model: "model-1", Wallet: "personal", Limit: "10"},
+		"model-2": {Model: "model-2", Wallet: "personal", Limit: "20"},
+	})
+}
+
+func (s *bulkSuite) TestContinueOnFailure(c *gc.C) {
+	results, err := s.client.Bulk([]budget.BulkOperation{
+		{Action: budget.BulkCreate, Model: "model-1", Wallet: "personal", Limit: "10"},
+		{Action: budget.BulkDelete, Model: "model-2"},
+		{Action: "frobnicate", Model: "model-3"},
+		{Action: budget.BulkCreate, Model: "model-4", Wallet: "personal", Limit: "10"},
+	}, budget.BulkConfig{})
+	c.Assert(err, gc.ErrorMatches, "2 of 4 budget operations failed")
+	c.Assert(results, gc.HasLen, 4)
+	c.Assert(results[0].Err, jc.ErrorIsNil)
+	c.Assert(results[1].Err, gc.ErrorMatches, "budget for model-2 not found")
+	c.Assert(errors.Cause(results[1].Err), gc.Equals, common.HTTPError{StatusCode: http.StatusNotFound, Message: "budget for model-2 not found"})
+	c.Assert(results[2].Err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(results[3].Err, jc.ErrorIsNil)
+	c.Assert(s.service.budgets, gc.HasLen, 2)
+}
+
+func (s *bulkSuite) TestStopOnFailure(c *gc.C) {
+	results, err := s.client.Bulk([]budget.BulkOperation{
+		{Action: budget.BulkCreate, Model: "model-1", Wallet: "personal", Limit: "10"},
+		{Action: budget.BulkDelete, Model: "model-2"},
+		{Action: budget.BulkCreate, Model: "model-3", Wallet: "personal", Limit: "10"},
+	}, budget.BulkConfig{Parallelism: 1, OnFailure: budget.StopOnFailure})
+	c.Assert(err, gc.ErrorMatches, "2 of 3 budget operations failed")
+	c.Assert(results[0].Err, jc.ErrorIsNil)
+	c.Assert(results[1].Err, gc.ErrorMatches, "budget for model-2 not found")
+	c.Assert(results[2].Err, gc.Equals, budget.ErrSkipped)
+	c.Assert(results[0].RolledBack, jc.IsFalse)
+	c.Assert(s.service.budgets, jc.DeepEquals, map[string]wireformat.Budget{
+		"model-1": {Model: "model-1", Wallet: "personal", Limit: "10"},
+	})
+}
+
+func (s *bulkSuite) TestRollbackOnFailure(c *gc.C) {
+	initial := map[string]wireformat.Budget{
+		"model-2": {Model: "model-2", Wallet: "personal", Limit: "5", Enforcement: wireformat.BlockPolicy},
+		"model-3": {Model: "model-3", Wallet: "team", Limit: "5", AlertThresholds: []int{50}},
+	}
+	for model, b := range initial {
+		s.service.budgets[model] = b
+	}
+	results, err := s.client.Bulk([]budget.BulkOperation{
+		{Action: budget.BulkCreate, Model: "model-1", Wallet: "personal", Limit: "10"},
+		{Action: budget.BulkUpdate, Model: "model-2", Limit: "20", Options: []budget.BudgetOption{budget.Enforcement(wireformat.NotifyPolicy)}},
+		{Action: budget.BulkDelete, Model: "model-3"},
+		{Action: budget.BulkUpdate, Model: "model-4", Limit: "20"},
+		{Action: budget.BulkCreate, Model: "model-5", Wallet: "personal", Limit: "10"},
+	}, budget.BulkConfig{Parallelism: 1, OnFailure: budget.RollbackOnFailure})
+	c.Assert(err, gc.ErrorMatches, "2 of 5 budget operations failed")
+	for i, r := range results[:3] {
+		c.Assert(r.Err, jc.ErrorIsNil, gc.Commentf("operation %d", i))
+		c.Assert(r.RollbackErr, jc.ErrorIsNil, gc.Commentf("operation %d", i))
+		c.Assert(r.RolledBack, jc.IsTrue, gc.Commentf("operation %d", i))
+	}
+	c.Assert(results[3].Err, gc.ErrorMatches, `cannot get budget for model "model-4": budget for model-4 not found`)
+	c.Assert(results[3].RolledBack, jc.IsFalse)
+	c.Assert(results[4].Err, gc.Equals, budget.ErrSkipped)
+	c.Assert(s.service.budgets, jc.DeepEquals, initial)
+}
+
+func (s *bulkSuite) TestRollbackEnforcement(c *gc.C) {
+	s.service.budgets["model-1"] = wireformat.Budget{Model: "model-1", Wallet: "personal", Limit: "5"}
+	results, err := s.client.Bulk([]budget.BulkOperation{
+		{Action: budget.BulkUpdate, Model: "model-1", Limit: "20", Options: []budget.BudgetOption{budget.Enforcement(wireformat.BlockPolicy)}},
+		{Action: budget.BulkDelete, Model: "model-2"},
+	}, budget.BulkConfig{Parallelism: 1, OnFailure: budget.RollbackOnFailure})
+	c.Assert(err, gc.ErrorMatches, "1 of 2 budget operations failed")
+	c.Assert(results[0].RollbackErr, jc.ErrorIsNil)
+	c.Assert(results[0].RolledBack, jc.IsTrue)
+	// The budget had no policy, which is treated as NotifyPolicy.
+	c.Assert(s.service.budgets["model-1"], jc.DeepEquals, wireformat.Budget{
+		Model:       "model-1",
+		Wallet:      "personal",
+		Limit:       "5",
+		Enforcement: wireformat.NotifyPolicy,
+	})
+}
+
+func (s *bulkSuite) TestRollbackWithoutWallet(c *gc.C) {
+	// Older services do not report the wallet of a budget.
+	initial := map[string]wireformat.Budget{
+		"model-1": {Model: "model-1", Limit: "5"},
+		"model-2": {Model: "model-2", Limit: "5"},
+	}
+	for model, b := range initial {
+		s.service.budgets[model] = b
+	}
+	results, err := s.client.Bulk([]budget.BulkOperation{
+		{Action: budget.BulkUpdate, Model: "model-1", Wallet: "team", Limit: "20"},
+		{Action: budget.BulkDelete, Model: "model-2"},
+	}, budget.BulkConfig{OnFailure: budget.RollbackOnFailure})
+	c.Assert(err, gc.ErrorMatches, "2 of 2 budget operations failed")
+	c.Assert(results[0].Err, gc.ErrorMatches, `cannot determine the wallet of the budget for model "model-1"`)
+	c.Assert(results[1].Err, gc.ErrorMatches, `cannot determine the wallet of the budget for model "model-2"`)
+	c.Assert(s.service.budgets, jc.DeepEquals, initial)
+
+	// Without rollback the wallet is not needed.
+	_, err = s.client.Bulk([]budget.BulkOperation{
+		{Action: budget.BulkDelete, Model: "model-2"},
+	}, budget.BulkConfig{})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *bulkSuite) TestRollbackFailure(c *gc.C) {
+	s.service.failDelete = true
+	results, err := s.client.Bulk([]budget.BulkOperation{
+		{Action: budget.BulkCreate, Model: "model-1", Wallet: "personal", Limit: "10"},
+		{Action: budget.BulkUpdate, Model: "model-2", Limit: "20"},
+	}, budget.BulkConfig{Parallelism: 1, OnFailure: budget.RollbackOnFailure})
+	c.Assert(err, gc.ErrorMatches, "1 of 2 budget operations failed")
+	c.Assert(results[0].Err, jc.ErrorIsNil)
+	c.Assert(results[0].RolledBack, jc.IsFalse)
+	c.Assert(results[0].RollbackErr, gc.ErrorMatches, `cannot roll back create of budget for model "model-1": service unavailable`)
+}
+
+func (s *bulkSuite) TestParallelism(c *gc.C) {
+	s.service.arrived = make(chan struct{}, 10)
+	s.service.release = make(chan struct{})
+	var ops []budget.BulkOperation
+	for i := 0; i < 10; i++ {
+		ops = append(ops, budget.BulkOperation{
+			Action: budget.BulkCreate,
+			Model:  fmt.Sprintf("model-%d", i),
+			Wallet: "personal",
+			Limit:  "10",
+		})
+	}
+	type bulkResult struct {
+		results []budget.BulkResult
+		err     error
+	}
+	done := make(chan bulkResult, 1)
+	go func() {
+		results, err := s.client.Bulk(ops, budget.BulkConfig{Parallelism: 3})
+		done <- bulkResult{results, err}
+	}()
+
+	// Three requests are in flight at once, and no more.
+	for i := 0; i < 3; i++ {
+		select {
+		case <-s.service.arrived:
+		case <-time.After(jujutesting.LongWait):
+			c.Fatalf("timed out waiting for request %d", i)
+		}
+	}
+	select {
+	case <-s.service.arrived:
+		c.Fatalf("more than 3 requests in flight")
+	case <-time.After(jujutesting.ShortWait):
+	}
+	close(s.service.release)
+
+	var r bulkResult
+	select {
+	case r = <-done:
+	case <-time.After(jujutesting.LongWait):
+		c.Fatalf("timed out waiting for bulk operations")
+	}
+	c.Assert(r.err, jc.ErrorIsNil)
+	c.Assert(r.results, gc.HasLen, 10)
+	for i, result := range r.results {
+		c.Assert(result.Operation.Model, gc.Equals, fmt.Sprintf("model-%d", i))
+		c.Assert(result.Err, jc.ErrorIsNil)
+	}
+	c.Assert(s.service.budgets, gc.HasLen, 10)
+	c.Assert(s.service.maxInFlight <= 3, jc.IsTrue, gc.Commentf("max in flight %d", s.service.maxInFlight))
+}
+
+// budgetService is a minimal budget service holding budgets in memory.
+type budgetService struct {
+	mu         sync.Mutex
+	budgets    map[string]wireformat.Budget
+	failDelete bool
+	// If release is set, requests are held until it is closed,
+	// after signalling on arrived.
+	arrived     chan struct{}
+	release     chan struct{}
+	inFlight    int
+	maxInFlight int
+}
+
+func (s *budgetService) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	s.inFlight++
+	if s.inFlight > s.maxInFlight {
+		s.maxInFlight = s.inFlight
+	}
+	s.mu.Unlock()
+	if s.release != nil {
+		s.arrived <- struct{}{}
+		<-s.release
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight--
+	status, response := s.serve(req)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (s *budgetService) serve(req *http.Request) (int, interface{}) {
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if req.Method == "POST" && len(parts) == 3 && parts[0] == "wallet" {
+		var create wireformat.CreateBudgetRequest
+		if err := json.NewDecoder(req.Body).Decode(&create); err != nil {
+			return http.StatusBadRequest, httpErr{Error: err.Error()}
+		}
+		if _, ok := s.budgets[create.Model]; ok {
+			return http.StatusConflict, httpErr{Error: "budget for " + create.Model + " exists"}
+		}
+		s.budgets[create.Model] = wireformat.Budget{
+			Model:       create.Model,
+			Wallet:      parts[1],
+			Limit:       create.Limit,
+			Enforcement: create.Enforcement,
+		}
+		return http.StatusOK, "created " + create.Model
+	}
+	if len(parts) != 3 || parts[0] != "model" {
+		return http.StatusNotFound, httpErr{Error: "not found"}
+	}
+	model := parts[1]
+	b, ok := s.budgets[model]
+	if !ok {
+		return http.StatusNotFound, httpErr{Error: "budget for " + model + " not found"}
+	}
+	switch req.Method {
+	case "GET":
+		return http.StatusOK, b
+	case "PATCH":
+		var update struct {
+			Update wireformat.UpdateBudgetRequest `json:"update"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&update); err != nil {
+			return http.StatusBadRequest, httpErr{Error: err.Error()}
+		}
+		if update.Update.Limit != "" {
+			b.Limit = update.Update.Limit
+		}
+		if update.Update.Wallet != "" {
+			b.Wallet = update.Update.Wallet
+		}
+		if update.Update.Enforcement != "" {
+			b.Enforcement = update.Update.Enforcement
+		}
+		if update.Update.AlertThresholds != nil {
+			b.AlertThresholds = update.Update.AlertThresholds
+		}
+		s.budgets[model] = b
+		return http.StatusOK, "updated " + model
+	case "DELETE":
+		if s.failDelete {
+			return http.StatusServiceUnavailable, httpErr{Error: "unavailable"}
+		}
+		delete(s.budgets, model)
+		return http.StatusOK, "deleted " + model
+	}
+	return http.StatusMethodNotAllowed, httpErr{Error: "method not allowed"}
+}
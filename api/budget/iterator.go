@@ -0,0 +1,180 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package budget
+
+import (
+	"github.com/juju/errors"
+
+	wireformat "github.com/juju/romulus/wireformat/budget"
+)
+
+// WalletIterator walks the wallets belonging to the current user,
+// retrieving a page at a time as required.
+//
+//	it := client.Wallets(50)
+//	for it.Next() {
+//		w := it.Wallet()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type WalletIterator struct {
+	c        *client
+	pageSize int
+	pager    pager
+	page     []wireformat.WalletSummary
+	current  wireformat.WalletSummary
+}
+
+// Wallets returns an iterator over the wallets belonging to the current
+// user that requests pageSize wallets at a time. A zero pageSize uses
+// the service default.
+func (c *client) Wallets(pageSize int) *WalletIterator {
+	return &WalletIterator{
+		c:        c,
+		pageSize: pageSize,
+		pager:    newPager(),
+	}
+}
+
+// Next advances the iterator to the next wallet. It returns false when
+// there are no more wallets or an error occurred.
+func (it *WalletIterator) Next() bool {
+	for len(it.page) == 0 {
+		cursor, ok := it.pager.nextPage()
+		if !ok {
+			return false
+		}
+		response, err := it.c.ListWalletsPage(cursor, it.pageSize)
+		if err != nil {
+			it.pager.fail(err)
+			return false
+		}
+		it.page = response.Wallets
+		it.pager.setNext(response.Next)
+	}
+	it.current, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Wallet returns the current wallet.
+func (it *WalletIterator) Wallet() wireformat.WalletSummary {
+	return it.current
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *WalletIterator) Err() error {
+	return it.pager.err
+}
+
+// BudgetIterator walks the budgets in a wallet, retrieving a page at
+// a time as required.
+type BudgetIterator struct {
+	c        *client
+	wallet   string
+	pageSize int
+	pager    pager
+	page     []wireformat.Budget
+	current  wireformat.Budget
+}
+
+// Budgets returns an iterator over the budgets in the wallet that
+// requests pageSize budgets at a time. A zero pageSize uses the service
+// default.
+func (c *client) Budgets(wallet string, pageSize int) *BudgetIterator {
+	return &BudgetIterator{
+		c:        c,
+		wallet:   wallet,
+		pageSize: pageSize,
+		pager:    newPager(),
+	}
+}
+
+// Next advances the iterator to the next budget. It returns false when
+// there are no more budgets or an error occurred.
+func (it *BudgetIterator) Next() bool {
+	for len(it.page) == 0 {
+		cursor, ok := it.pager.nextPage()
+		if !ok {
+			return false
+		}
+		response, err := it.c.GetWalletPage(it.wallet, cursor, it.pageSize)
+		if err != nil {
+			it.pager.fail(err)
+			return false
+		}
+		it.page = response.Budgets
+		it.pager.setNext(response.Next)
+	}
+	it.current, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Budget returns the current budget.
+func (it *BudgetIterator) Budget() wireformat.Budget {
+	return it.current
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *BudgetIterator) Err() error {
+	return it.pager.err
+}
+
+// pager tracks the cursor of the next page to retrieve.
+type pager struct {
+	started bool
+	next    string
+	cursors cursorSet
+	err     error
+}
+
+func newPager() pager {
+	return pager{cursors: newCursorSet()}
+}
+
+// nextPage returns the cursor of the next page, and false if there
+// are no more pages. Servers that do not paginate return no cursor
+// with the first page, which is then the last.
+func (p *pager) nextPage() (string, bool) {
+	if p.err != nil {
+		return "", false
+	}
+	if !p.started {
+		p.started = true
+		return "", true
+	}
+	if p.next == "" {
+		return "", false
+	}
+	if err := p.cursors.add(p.next); err != nil {
+		p.err = err
+		return "", false
+	}
+	return p.next, true
+}
+
+func (p *pager) setNext(next string) {
+	p.next = next
+}
+
+func (p *pager) fail(err error) {
+	p.err = err
+}
+
+// cursorSet records the cursors already requested so that a server
+// returning the same cursor repeatedly cannot cause an endless loop.
+type cursorSet map[string]bool
+
+func newCursorSet() cursorSet {
+	return make(cursorSet)
+}
+
+func (s cursorSet) add(cursor string) error {
+	if s[cursor] {
+		return errors.Errorf("pagination cursor %q repeated", cursor)
+	}
+	s[cursor] = true
+	return nil
+}
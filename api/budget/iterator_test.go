@@ -0,0 +1,129 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package budget_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/romulus/api/budget"
+	wireformat "github.com/juju/romulus/wireformat/budget"
+)
+
+type iteratorSuite struct{}
+
+var _ = gc.Suite(&iteratorSuite{})
+
+func (s *iteratorSuite) TestWallets(c *gc.C) {
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBodies: marshalAll(c,
+			wireformat.ListWalletsResponse{
+				Wallets: wireformat.WalletSummaries{{Wallet: "a"}, {Wallet: "b"}},
+				Next:    "page-2",
+			},
+			wireformat.ListWalletsResponse{Next: "page-3"},
+			wireformat.ListWalletsResponse{
+				Wallets: wireformat.WalletSummaries{{Wallet: "c"}},
+			},
+		),
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	it := client.Wallets(2)
+	var wallets []string
+	for it.Next() {
+		wallets = append(wallets, it.Wallet().Wallet)
+	}
+	c.Assert(it.Err(), jc.ErrorIsNil)
+	c.Assert(wallets, jc.DeepEquals, []string{"a", "b", "c"})
+	var urls []string
+	for _, call := range httpClient.Calls() {
+		urls = append(urls, call.Args[2].(string))
+	}
+	c.Assert(urls, jc.DeepEquals, []string{
+		"https://api.jujucharms.com/omnibus/v3/wallet?limit=2",
+		"https://api.jujucharms.com/omnibus/v3/wallet?cursor=page-2&limit=2",
+		"https://api.jujucharms.com/omnibus/v3/wallet?cursor=page-3&limit=2",
+	})
+}
+
+func (s *iteratorSuite) TestWalletsUnpaginated(c *gc.C) {
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBodies: marshalAll(c, wireformat.ListWalletsResponse{
+			Wallets: wireformat.WalletSummaries{{Wallet: "a"}, {Wallet: "b"}, {Wallet: "c"}},
+		}),
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	it := client.Wallets(0)
+	n := 0
+	for it.Next() {
+		n++
+	}
+	c.Assert(it.Err(), jc.ErrorIsNil)
+	c.Assert(n, gc.Equals, 3)
+	c.Assert(httpClient.Calls(), gc.HasLen, 1)
+}
+
+func (s *iteratorSuite) TestWalletsError(c *gc.C) {
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBodies: marshalAll(c, wireformat.ListWalletsResponse{
+			Wallets: wireformat.WalletSummaries{{Wallet: "a"}},
+			Next:    "page-2",
+		}),
+	}
+	httpClient.SetErrors(nil, errors.New("bogus error"))
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	it := client.Wallets(1)
+	c.Assert(it.Next(), jc.IsTrue)
+	c.Assert(it.Wallet().Wallet, gc.Equals, "a")
+	c.Assert(it.Next(), jc.IsFalse)
+	c.Assert(it.Err(), gc.ErrorMatches, ".*bogus error")
+	c.Assert(it.Next(), jc.IsFalse)
+	c.Assert(httpClient.Calls(), gc.HasLen, 2)
+}
+
+func (s *iteratorSuite) TestBudgets(c *gc.C) {
+	httpClient := &mockClient{
+		RespCode: http.StatusOK,
+		RespBodies: marshalAll(c,
+			wireformat.WalletWithBudgets{
+				Budgets: []wireformat.Budget{{Model: "model-a"}},
+				Next:    "page-2",
+			},
+			wireformat.WalletWithBudgets{
+				Budgets: []wireformat.Budget{{Model: "model-b"}},
+			},
+		),
+	}
+	client, err := budget.NewClient(budget.HTTPClient(httpClient))
+	c.Assert(err, jc.ErrorIsNil)
+	it := client.Budgets("personal", 1)
+	var models []string
+	for it.Next() {
+		models = append(models, it.Budget().Model)
+	}
+	c.Assert(it.Err(), jc.ErrorIsNil)
+	c.Assert(models, jc.DeepEquals, []string{"model-a", "model-b"})
+	c.Assert(httpClient.Calls()[1].Args[2], gc.Equals, "https://api.jujucharms.com/omnibus/v3/wallet/personal?cursor=page-2&limit=1")
+}
+
+// marshalAll returns the JSON encoding of each value.
+func marshalAll(c *gc.C, values ...interface{}) [][]byte {
+	bodies := make([][]byte, len(values))
+	for i, v := range values {
+		data, err := json.Marshal(v)
+		c.Assert(err, jc.ErrorIsNil)
+		bodies[i] = data
+	}
+	return bodies
+}
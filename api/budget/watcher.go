@@ -0,0 +1,233 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package budget
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+
+	wireformat "github.com/juju/romulus/wireformat/budget"
+)
+
+// DefaultAlertThresholds holds the usage percentages at which alerts
+// are raised for wallets, and for budgets without thresholds of their own.
+var DefaultAlertThresholds = []int{50, 80, 100}
+
+// WalletClient defines the budget api calls used by the Watcher.
+type WalletClient interface {
+	// ListWallets lists the wallets belonging to the current user.
+	ListWallets() (*wireformat.ListWalletsResponse, error)
+
+	// GetWallet returns the information of a particular wallet.
+	GetWallet(wallet string) (*wireformat.WalletWithBudgets, error)
+}
+
+// AlertKind is the kind of an alert raised by the Watcher.
+type AlertKind string
+
+const (
+	// ThresholdCrossed is raised when usage reaches an alert threshold.
+	ThresholdCrossed AlertKind = "threshold-crossed"
+	// OverLimit is raised when consumption exceeds the limit.
+	OverLimit AlertKind = "over-limit"
+)
+
+// Alert is sent by the Watcher when a wallet or budget crosses an
+// alert threshold or goes over its limit.
+type Alert struct {
+	Kind AlertKind
+	// Wallet holds the name of the wallet.
+	Wallet string
+	// Model holds the model of the budget, and is empty for
+	// wallet alerts.
+	Model string
+	// Threshold holds the threshold crossed, for ThresholdCrossed
+	// alerts.
+	Threshold int
+	// Usage holds the usage percentage when the alert was raised.
+	Usage float64
+	// Err holds the error that prevented the wallets from being
+	// polled. All other fields are empty when it is set.
+	Err error
+}
+
+// WatcherConfig holds the configuration of a Watcher.
+type WatcherConfig struct {
+	// Client is used to retrieve the wallets and their budgets.
+	Client WalletClient
+	// Clock is used to schedule polls. It defaults to the wall clock.
+	Clock clock.Clock
+	// Interval holds the time between polls.
+	Interval time.Duration
+	// Thresholds holds the alert thresholds for wallets, and for
+	// budgets without thresholds of their own. It defaults to
+	// DefaultAlertThresholds.
+	Thresholds []int
+}
+
+// Validate checks the WatcherConfig for errors.
+func (config WatcherConfig) Validate() error {
+	if config.Client == nil {
+		return errors.NotValidf("nil Client")
+	}
+	if config.Interval <= 0 {
+		return errors.NotValidf("non-positive Interval")
+	}
+	return nil
+}
+
+// Watcher polls the user's wallets and sends alerts when a wallet or
+// budget crosses an alert threshold or goes over its limit. Thresholds
+// already reached when a wallet or budget is first seen are reported
+// on the first poll.
+type Watcher struct {
+	config WatcherConfig
+	alerts chan Alert
+	usage  map[usageKey]float64
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// usageKey identifies a wallet, or a budget within a wallet.
+type usageKey struct {
+	wallet string
+	model  string
+}
+
+// NewWatcher starts a new Watcher with the given configuration.
+func NewWatcher(config WatcherConfig) (*Watcher, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if config.Clock == nil {
+		config.Clock = clock.WallClock
+	}
+	if config.Thresholds == nil {
+		config.Thresholds = DefaultAlertThresholds
+	}
+	w := &Watcher{
+		config: config,
+		alerts: make(chan Alert),
+		usage:  make(map[usageKey]float64),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go w.loop()
+	return w, nil
+}
+
+// Alerts returns the channel on which alerts are sent. It is closed
+// when the watcher stops.
+func (w *Watcher) Alerts() <-chan Alert {
+	return w.alerts
+}
+
+// Stop stops the watcher and waits for it to finish.
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+	<-w.done
+}
+
+func (w *Watcher) loop() {
+	defer close(w.done)
+	defer close(w.alerts)
+	for {
+		for _, alert := range w.poll() {
+			select {
+			case w.alerts <- alert:
+			case <-w.stop:
+				return
+			}
+		}
+		select {
+		case <-w.config.Clock.After(w.config.Interval):
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// poll retrieves the wallets and their budgets and returns the alerts
+// raised since the previous poll.
+func (w *Watcher) poll() []Alert {
+	wallets, err := w.config.Client.ListWallets()
+	if err != nil {
+		return []Alert{{Err: errors.Annotate(err, "failed to list wallets")}}
+	}
+	var alerts []Alert
+	for _, summary := range wallets.Wallets {
+		key := usageKey{wallet: summary.Wallet}
+		alerts = append(alerts, w.check(key, summary.Limit, summary.Consumed, w.config.Thresholds)...)
+
+		wallet, err := w.config.Client.GetWallet(summary.Wallet)
+		if err != nil {
+			alerts = append(alerts, Alert{Err: errors.Annotatef(err, "failed to get wallet %q", summary.Wallet)})
+			continue
+		}
+		for _, b := range wallet.Budgets {
+			thresholds := b.AlertThresholds
+			if len(thresholds) == 0 {
+				thresholds = w.config.Thresholds
+			}
+			key := usageKey{wallet: summary.Wallet, model: b.Model}
+			alerts = append(alerts, w.check(key, b.Limit, b.Consumed, thresholds)...)
+		}
+	}
+	return alerts
+}
+
+// check records the usage of a wallet or budget and returns the
+// alerts raised since its usage was last recorded.
+func (w *Watcher) check(key usageKey, limit, consumed string, thresholds []int) []Alert {
+	usage, ok := usagePercent(limit, consumed)
+	if !ok {
+		return nil
+	}
+	previous := w.usage[key]
+	w.usage[key] = usage
+
+	sorted := append([]int(nil), thresholds...)
+	sort.Ints(sorted)
+	var alerts []Alert
+	for _, t := range sorted {
+		if previous < float64(t) && usage >= float64(t) {
+			alerts = append(alerts, Alert{
+				Kind:      ThresholdCrossed,
+				Wallet:    key.wallet,
+				Model:     key.model,
+				Threshold: t,
+				Usage:     usage,
+			})
+		}
+	}
+	if previous <= 100 && usage > 100 {
+		alerts = append(alerts, Alert{
+			Kind:   OverLimit,
+			Wallet: key.wallet,
+			Model:  key.model,
+			Usage:  usage,
+		})
+	}
+	return alerts
+}
+
+// usagePercent returns consumed as a percentage of limit.
+func usagePercent(limit, consumed string) (float64, bool) {
+	l, err := strconv.ParseFloat(limit, 64)
+	if err != nil || l <= 0 {
+		return 0, false
+	}
+	c, err := strconv.ParseFloat(consumed, 64)
+	if err != nil {
+		return 0, false
+	}
+	return c / l * 100, true
+}
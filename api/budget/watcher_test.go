@@ -0,0 +1,182 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package budget_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/romulus/api/budget"
+	wireformat "github.com/juju/romulus/wireformat/budget"
+)
+
+type watcherSuite struct{}
+
+var _ = gc.Suite(&watcherSuite{})
+
+func (s *watcherSuite) TestAlerts(c *gc.C) {
+	client := &mockWalletClient{
+		wallets: map[string]*wireformat.WalletWithBudgets{
+			"personal": {
+				Limit: "100",
+				Total: wireformat.WalletTotals{Consumed: "10"},
+				Budgets: []wireformat.Budget{{
+					Model:           "model-a",
+					Limit:           "20",
+					Consumed:        "12",
+					AlertThresholds: []int{25, 75},
+				}, {
+					Model:    "model-b",
+					Limit:    "40",
+					Consumed: "0",
+				}},
+			},
+		},
+	}
+	clock := testclock.NewClock(time.Now())
+	w, err := budget.NewWatcher(budget.WatcherConfig{
+		Client:   client,
+		Clock:    clock,
+		Interval: time.Minute,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer w.Stop()
+
+	// Thresholds already reached are reported on the first poll.
+	c.Assert(nextAlert(c, w), jc.DeepEquals, budget.Alert{
+		Kind:      budget.ThresholdCrossed,
+		Wallet:    "personal",
+		Model:     "model-a",
+		Threshold: 25,
+		Usage:     60,
+	})
+
+	client.update("personal", "80", []string{"12", "50"})
+	c.Assert(clock.WaitAdvance(time.Minute, time.Second, 1), jc.ErrorIsNil)
+	c.Assert(nextAlert(c, w), jc.DeepEquals, budget.Alert{
+		Kind:      budget.ThresholdCrossed,
+		Wallet:    "personal",
+		Threshold: 50,
+		Usage:     80,
+	})
+	c.Assert(nextAlert(c, w), jc.DeepEquals, budget.Alert{
+		Kind:      budget.ThresholdCrossed,
+		Wallet:    "personal",
+		Threshold: 80,
+		Usage:     80,
+	})
+	for _, threshold := range []int{50, 80, 100} {
+		c.Assert(nextAlert(c, w), jc.DeepEquals, budget.Alert{
+			Kind:      budget.ThresholdCrossed,
+			Wallet:    "personal",
+			Model:     "model-b",
+			Threshold: threshold,
+			Usage:     125,
+		})
+	}
+	c.Assert(nextAlert(c, w), jc.DeepEquals, budget.Alert{
+		Kind:   budget.OverLimit,
+		Wallet: "personal",
+		Model:  "model-b",
+		Usage:  125,
+	})
+
+	// Nothing is reported while usage is unchanged.
+	c.Assert(clock.WaitAdvance(time.Minute, time.Second, 1), jc.ErrorIsNil)
+	c.Assert(clock.WaitAdvance(time.Minute, time.Second, 1), jc.ErrorIsNil)
+	select {
+	case alert := <-w.Alerts():
+		c.Fatalf("unexpected alert %#v", alert)
+	default:
+	}
+}
+
+func (s *watcherSuite) TestPollError(c *gc.C) {
+	client := &mockWalletClient{err: errors.New("bogus error")}
+	w, err := budget.NewWatcher(budget.WatcherConfig{
+		Client:   client,
+		Clock:    testclock.NewClock(time.Now()),
+		Interval: time.Minute,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer w.Stop()
+	alert := nextAlert(c, w)
+	c.Assert(alert.Err, gc.ErrorMatches, "failed to list wallets: bogus error")
+}
+
+func (s *watcherSuite) TestStop(c *gc.C) {
+	w, err := budget.NewWatcher(budget.WatcherConfig{
+		Client:   &mockWalletClient{},
+		Clock:    testclock.NewClock(time.Now()),
+		Interval: time.Minute,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	w.Stop()
+	_, ok := <-w.Alerts()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *watcherSuite) TestInvalidConfig(c *gc.C) {
+	_, err := budget.NewWatcher(budget.WatcherConfig{Interval: time.Minute})
+	c.Assert(err, gc.ErrorMatches, "nil Client not valid")
+	_, err = budget.NewWatcher(budget.WatcherConfig{Client: &mockWalletClient{}})
+	c.Assert(err, gc.ErrorMatches, "non-positive Interval not valid")
+}
+
+func nextAlert(c *gc.C, w *budget.Watcher) budget.Alert {
+	select {
+	case alert := <-w.Alerts():
+		return alert
+	case <-time.After(5 * time.Second):
+		c.Fatalf("timed out waiting for alert")
+	}
+	panic("unreachable")
+}
+
+type mockWalletClient struct {
+	mu      sync.Mutex
+	wallets map[string]*wireformat.WalletWithBudgets
+	err     error
+}
+
+// update sets the consumption of the wallet and of each of its budgets.
+func (m *mockWalletClient) update(wallet, consumed string, budgets []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w := m.wallets[wallet]
+	w.Total.Consumed = consumed
+	for i, b := range budgets {
+		w.Budgets[i].Consumed = b
+	}
+}
+
+func (m *mockWalletClient) ListWallets() (*wireformat.ListWalletsResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	var response wireformat.ListWalletsResponse
+	for name, w := range m.wallets {
+		response.Wallets = append(response.Wallets, wireformat.WalletSummary{
+			Wallet:   name,
+			Limit:    w.Limit,
+			Consumed: w.Total.Consumed,
+		})
+	}
+	return &response, nil
+}
+
+func (m *mockWalletClient) GetWallet(wallet string) (*wireformat.WalletWithBudgets, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w := *m.wallets[wallet]
+	w.Budgets = append([]wireformat.Budget(nil), w.Budgets...)
+	return &w, nil
+}
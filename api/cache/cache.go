@@ -0,0 +1,186 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package cache provides an opt-in cache of the responses to the read
+// requests sent by the romulus API clients.
+package cache
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+)
+
+// DefaultTTLs holds the time for which the responses to each operation
+// are cached by default.
+var DefaultTTLs = map[string]time.Duration{
+	"plan.GetAssociatedPlans": 10 * time.Minute,
+	"budget.ListWallets":      time.Minute,
+	"budget.GetWallet":        time.Minute,
+}
+
+// Config holds the configuration of a Cache.
+type Config struct {
+	// TTLs holds the time for which the responses to each operation,
+	// such as "budget.GetWallet", are cached. Operations without a
+	// TTL are not cached. It defaults to DefaultTTLs.
+	TTLs map[string]time.Duration
+	// Clock is used to expire cached responses. It defaults to the
+	// wall clock.
+	Clock clock.Clock
+}
+
+// Validate checks the Config for errors.
+func (config Config) Validate() error {
+	for operation, ttl := range config.TTLs {
+		if ttl < 0 {
+			return errors.NotValidf("negative TTL for %q", operation)
+		}
+	}
+	return nil
+}
+
+// Cache holds the responses to GET requests for the time configured
+// for their operation. Once a response has expired it is revalidated
+// with If-None-Match if the service sent an ETag for it.
+//
+// Responses are cached by URL, so a Cache should only be shared by
+// clients that use the same credentials.
+type Cache struct {
+	config Config
+
+	mu      sync.Mutex
+	entries map[string]entry
+	// generation is incremented whenever entries are invalidated, so
+	// that responses to requests sent before then are not cached.
+	generation int
+}
+
+type entry struct {
+	operation string
+	header    http.Header
+	body      []byte
+	etag      string
+	expires   time.Time
+}
+
+// New returns a new Cache with the given configuration.
+func New(config Config) (*Cache, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if config.TTLs == nil {
+		config.TTLs = DefaultTTLs
+	}
+	if config.Clock == nil {
+		config.Clock = clock.WallClock
+	}
+	return &Cache{
+		config:  config,
+		entries: make(map[string]entry),
+	}, nil
+}
+
+// Do returns the cached response to the request if there is a fresh
+// one, and otherwise sends the request using do, caching successful
+// responses. Requests are sent uncached if c is nil, the request is not
+// a GET or the operation has no TTL.
+func (c *Cache) Do(operation string, req *http.Request, do func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	if c == nil || req.Method != "GET" {
+		return do(req)
+	}
+	ttl := c.config.TTLs[operation]
+	if ttl <= 0 {
+		return do(req)
+	}
+	key := req.URL.String()
+	now := c.config.Clock.Now()
+
+	c.mu.Lock()
+	cached, ok := c.entries[key]
+	generation := c.generation
+	c.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.response(req), nil
+	}
+	if ok && cached.etag != "" {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := do(req)
+	if err != nil {
+		return nil, err
+	}
+	if ok && resp.StatusCode == http.StatusNotModified {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		cached.expires = now.Add(ttl)
+		c.store(key, cached, generation)
+		return cached.response(req), nil
+	}
+	if resp.StatusCode != http.StatusOK || strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		return resp, nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	c.store(key, entry{
+		operation: operation,
+		header:    resp.Header.Clone(),
+		body:      body,
+		etag:      resp.Header.Get("ETag"),
+		expires:   now.Add(ttl),
+	}, generation)
+	return resp, nil
+}
+
+// store caches the entry, unless entries have been invalidated since
+// the given generation.
+func (c *Cache) store(key string, e entry, generation int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation == generation {
+		c.entries[key] = e
+	}
+}
+
+// Invalidate removes the cached responses to operations whose names
+// begin with prefix, such as "budget.". It does nothing if c is nil.
+func (c *Cache) Invalidate(prefix string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.entries {
+		if strings.HasPrefix(e.operation, prefix) {
+			delete(c.entries, key)
+		}
+	}
+	c.generation++
+}
+
+// response returns a response holding the cached entry.
+func (e entry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
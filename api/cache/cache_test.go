@@ -0,0 +1,184 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cache_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/romulus/api/cache"
+)
+
+func Test(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type cacheSuite struct {
+	clock *testclock.Clock
+	cache *cache.Cache
+	// requests holds the If-None-Match header of each request sent.
+	requests []string
+	status   int
+	etag     string
+	body     string
+}
+
+var _ = gc.Suite(&cacheSuite{})
+
+func (s *cacheSuite) SetUpTest(c *gc.C) {
+	s.clock = testclock.NewClock(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC))
+	s.requests = nil
+	s.status = http.StatusOK
+	s.etag = ""
+	s.body = `{"limit":"10"}`
+	var err error
+	s.cache, err = cache.New(cache.Config{
+		TTLs:  map[string]time.Duration{"budget.GetWallet": time.Minute},
+		Clock: s.clock,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *cacheSuite) do(req *http.Request) (*http.Response, error) {
+	s.requests = append(s.requests, req.Header.Get("If-None-Match"))
+	header := make(http.Header)
+	if s.etag != "" {
+		header.Set("ETag", s.etag)
+	}
+	return &http.Response{
+		StatusCode: s.status,
+		Header:     header,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(s.body))),
+	}, nil
+}
+
+func (s *cacheSuite) get(c *gc.C, operation, url string) string {
+	req, err := http.NewRequest("GET", url, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := s.cache.Do(operation, req, s.do)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	data, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	return string(data)
+}
+
+func (s *cacheSuite) TestCached(c *gc.C) {
+	c.Assert(s.get(c, "budget.GetWallet", "https://api.example.com/wallet/personal"), gc.Equals, `{"limit":"10"}`)
+	s.body = `{"limit":"20"}`
+	c.Assert(s.get(c, "budget.GetWallet", "https://api.example.com/wallet/personal"), gc.Equals, `{"limit":"10"}`)
+	c.Assert(s.requests, gc.HasLen, 1)
+
+	// Responses are cached by URL.
+	c.Assert(s.get(c, "budget.GetWallet", "https://api.example.com/wallet/team"), gc.Equals, `{"limit":"20"}`)
+	c.Assert(s.requests, gc.HasLen, 2)
+}
+
+func (s *cacheSuite) TestExpired(c *gc.C) {
+	s.get(c, "budget.GetWallet", "https://api.example.com/wallet/personal")
+	s.clock.Advance(time.Minute)
+	s.body = `{"limit":"20"}`
+	c.Assert(s.get(c, "budget.GetWallet", "https://api.example.com/wallet/personal"), gc.Equals, `{"limit":"20"}`)
+	c.Assert(s.requests, jc.DeepEquals, []string{"", ""})
+}
+
+func (s *cacheSuite) TestRevalidate(c *gc.C) {
+	s.etag = `"v1"`
+	s.get(c, "budget.GetWallet", "https://api.example.com/wallet/personal")
+	s.clock.Advance(time.Minute)
+
+	s.status = http.StatusNotModified
+	s.body = ""
+	c.Assert(s.get(c, "budget.GetWallet", "https://api.example.com/wallet/personal"), gc.Equals, `{"limit":"10"}`)
+	c.Assert(s.requests, jc.DeepEquals, []string{"", `"v1"`})
+
+	// The revalidated response is fresh for another TTL.
+	s.clock.Advance(30 * time.Second)
+	c.Assert(s.get(c, "budget.GetWallet", "https://api.example.com/wallet/personal"), gc.Equals, `{"limit":"10"}`)
+	c.Assert(s.requests, gc.HasLen, 2)
+
+	s.clock.Advance(30 * time.Second)
+	s.status = http.StatusOK
+	s.etag = `"v2"`
+	s.body = `{"limit":"20"}`
+	c.Assert(s.get(c, "budget.GetWallet", "https://api.example.com/wallet/personal"), gc.Equals, `{"limit":"20"}`)
+	c.Assert(s.requests, jc.DeepEquals, []string{"", `"v1"`, `"v1"`})
+}
+
+func (s *cacheSuite) TestNotCached(c *gc.C) {
+	// Operations without a TTL are not cached.
+	s.get(c, "budget.ListBudgets", "https://api.example.com/budget")
+	s.get(c, "budget.ListBudgets", "https://api.example.com/budget")
+	c.Assert(s.requests, gc.HasLen, 2)
+
+	// Nor are error responses.
+	s.status = http.StatusNotFound
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest("GET", "https://api.example.com/wallet/personal", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		resp, err := s.cache.Do("budget.GetWallet", req, s.do)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(resp.StatusCode, gc.Equals, http.StatusNotFound)
+	}
+	c.Assert(s.requests, gc.HasLen, 4)
+
+	// Nor are requests other than GET.
+	s.status = http.StatusOK
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest("PATCH", "https://api.example.com/wallet/personal", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = s.cache.Do("budget.GetWallet", req, s.do)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(s.requests, gc.HasLen, 6)
+}
+
+func (s *cacheSuite) TestInvalidate(c *gc.C) {
+	s.get(c, "budget.GetWallet", "https://api.example.com/wallet/personal")
+	s.cache.Invalidate("plan.")
+	s.get(c, "budget.GetWallet", "https://api.example.com/wallet/personal")
+	c.Assert(s.requests, gc.HasLen, 1)
+
+	s.cache.Invalidate("budget.")
+	s.get(c, "budget.GetWallet", "https://api.example.com/wallet/personal")
+	c.Assert(s.requests, gc.HasLen, 2)
+}
+
+func (s *cacheSuite) TestInvalidateDuringRequest(c *gc.C) {
+	req, err := http.NewRequest("GET", "https://api.example.com/wallet/personal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.cache.Do("budget.GetWallet", req, func(req *http.Request) (*http.Response, error) {
+		s.cache.Invalidate("budget.")
+		return s.do(req)
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	// The response may predate the invalidation, so it is not cached.
+	s.get(c, "budget.GetWallet", "https://api.example.com/wallet/personal")
+	c.Assert(s.requests, gc.HasLen, 2)
+}
+
+func (s *cacheSuite) TestNilCache(c *gc.C) {
+	var nilCache *cache.Cache
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest("GET", "https://api.example.com/wallet/personal", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = nilCache.Do("budget.GetWallet", req, s.do)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	nilCache.Invalidate("budget.")
+	c.Assert(s.requests, gc.HasLen, 2)
+}
+
+func (s *cacheSuite) TestConfigValidate(c *gc.C) {
+	_, err := cache.New(cache.Config{TTLs: map[string]time.Duration{"budget.GetWallet": -time.Second}})
+	c.Assert(err, gc.ErrorMatches, `negative TTL for "budget.GetWallet" not valid`)
+}
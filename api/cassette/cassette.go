@@ -0,0 +1,201 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package cassette provides an http client that records the requests
+// sent by the romulus API clients, and their responses, to a cassette
+// file, and one that replays a cassette so that code built on the API
+// clients can be tested without a running service.
+//
+// Credentials, macaroons and authorization data are scrubbed from the
+// recorded interactions before they are saved.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/juju/errors"
+	"gopkg.in/macaroon.v2"
+
+	"github.com/juju/romulus/api/logging"
+)
+
+// HTTPClient defines the interface of the http client used to send
+// recorded requests.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Cassette holds a sequence of recorded interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction holds a request and the response received for it.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request holds the recorded details of a request.
+type Request struct {
+	Method string `json:"method"`
+	// Path holds the path and query of the request URL. The scheme
+	// and host are not recorded, so that a cassette may be replayed
+	// against another host.
+	Path   string      `json:"path"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response holds the recorded details of a response.
+type Response struct {
+	StatusCode int         `json:"status-code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Load reads a cassette from the file at path.
+func Load(path string) (*Cassette, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read cassette")
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, errors.Annotatef(err, "cannot parse cassette %q", path)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to the file at path.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := ioutil.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return errors.Annotate(err, "cannot write cassette")
+	}
+	return nil
+}
+
+// newRequest returns the scrubbed record of the request.
+func newRequest(req *http.Request) (Request, error) {
+	body, err := requestBody(req)
+	if err != nil {
+		return Request{}, errors.Trace(err)
+	}
+	return Request{
+		Method: req.Method,
+		Path:   requestPath(req.URL),
+		Header: logging.RedactHeader(req.Header),
+		Body:   Scrub(body),
+	}, nil
+}
+
+// requestPath returns the path and query of the URL.
+func requestPath(u *url.URL) string {
+	return (&url.URL{Path: u.Path, RawPath: u.RawPath, RawQuery: u.RawQuery}).String()
+}
+
+// requestBody returns the body of the request, leaving the request able
+// to be sent.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		defer body.Close()
+		return ioutil.ReadAll(body)
+	}
+	if seeker, ok := req.Body.(io.ReadSeeker); ok {
+		data, err := ioutil.ReadAll(seeker)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return data, nil
+	}
+	data, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// Scrub returns the body with any credentials, macaroons and
+// authorization data replaced by placeholders that decode to values
+// of the same type, so that scrubbed responses can still be replayed.
+// Bodies that are not JSON are returned unchanged.
+func Scrub(data []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return string(data)
+	}
+	scrubbed, err := json.Marshal(scrub(v, false))
+	if err != nil {
+		return string(data)
+	}
+	return string(scrubbed)
+}
+
+// scrub returns v with secret values replaced. If secret is true, v is
+// held in a secret field and all of it is replaced.
+func scrub(v interface{}, secret bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if logging.IsMacaroon(v) {
+			return placeholderMacaroon(v)
+		}
+		for key, value := range v {
+			v[key] = scrub(value, secret || logging.IsSecret(key))
+		}
+		return v
+	case []interface{}:
+		for i, value := range v {
+			v[i] = scrub(value, secret)
+		}
+		return v
+	case nil:
+		return v
+	}
+	if secret {
+		return logging.Redacted
+	}
+	return v
+}
+
+// placeholderMacaroon returns a macaroon, encoded in the same version as
+// m, that holds no secrets.
+func placeholderMacaroon(m map[string]interface{}) interface{} {
+	version := macaroon.V1
+	if _, ok := m["identifier"]; !ok {
+		version = macaroon.V2
+	}
+	placeholder, err := macaroon.New([]byte(logging.Redacted), []byte(logging.Redacted), "", version)
+	if err != nil {
+		return logging.Redacted
+	}
+	data, err := json.Marshal(placeholder)
+	if err != nil {
+		return logging.Redacted
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return logging.Redacted
+	}
+	return v
+}
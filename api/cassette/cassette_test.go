@@ -0,0 +1,172 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cassette_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	stdtesting "testing"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/v3"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/macaroon.v2"
+
+	"github.com/juju/romulus/api/auth"
+	"github.com/juju/romulus/api/budget"
+	"github.com/juju/romulus/api/cassette"
+	"github.com/juju/romulus/api/sla"
+	wireformat "github.com/juju/romulus/wireformat/budget"
+	slawireformat "github.com/juju/romulus/wireformat/sla"
+)
+
+func Test(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type cassetteSuite struct {
+	server *httptest.Server
+	path   string
+}
+
+var _ = gc.Suite(&cassetteSuite{})
+
+func (s *cassetteSuite) SetUpTest(c *gc.C) {
+	limits := map[string]string{}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		c.Check(req.Header.Get("Authorization"), gc.Equals, "Bearer secret")
+		switch {
+		case req.Method == "POST" && req.URL.Path == "/wallet":
+			var create wireformat.CreateWalletRequest
+			c.Check(json.NewDecoder(req.Body).Decode(&create), jc.ErrorIsNil)
+			limits[create.Wallet] = create.Limit
+			json.NewEncoder(w).Encode("wallet created")
+		case req.Method == "GET" && strings.HasPrefix(req.URL.Path, "/wallet/"):
+			limit, ok := limits[strings.TrimPrefix(req.URL.Path, "/wallet/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(map[string]string{"error": "wallet not found"})
+				return
+			}
+			json.NewEncoder(w).Encode(wireformat.WalletWithBudgets{Limit: limit})
+		case req.Method == "POST" && req.URL.Path == "/sla/authorize":
+			m, err := macaroon.New([]byte("root-key"), []byte("id"), "sla", macaroon.LatestVersion)
+			c.Check(err, jc.ErrorIsNil)
+			json.NewEncoder(w).Encode(slawireformat.SLAResponse{Owner: "bob", Credentials: m})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	s.path = filepath.Join(c.MkDir(), "cassette.json")
+}
+
+func (s *cassetteSuite) TearDownTest(c *gc.C) {
+	s.server.Close()
+}
+
+func (s *cassetteSuite) record(c *gc.C, f func(h cassette.HTTPClient)) {
+	recorder := cassette.NewRecorder(s.server.Client())
+	f(recorder)
+	err := recorder.Save(s.path)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *cassetteSuite) replayer(c *gc.C) *cassette.Replayer {
+	cas, err := cassette.Load(s.path)
+	c.Assert(err, jc.ErrorIsNil)
+	return cassette.NewReplayer(cas)
+}
+
+func (s *cassetteSuite) budgetClient(c *gc.C, apiRoot string, h cassette.HTTPClient) budget.Client {
+	client, err := budget.NewClient(
+		budget.APIRoot(apiRoot),
+		budget.HTTPClient(h),
+		budget.Authentication(auth.BearerToken("secret")),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	return client
+}
+
+func (s *cassetteSuite) TestRecordReplay(c *gc.C) {
+	s.record(c, func(h cassette.HTTPClient) {
+		client := s.budgetClient(c, s.server.URL, h)
+		_, err := client.GetWallet("personal")
+		c.Assert(err, gc.ErrorMatches, "wallet not found")
+		_, err = client.CreateWallet("personal", "100")
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = client.GetWallet("personal")
+		c.Assert(err, jc.ErrorIsNil)
+	})
+
+	// The cassette is replayed against another host, without the service.
+	replayer := s.replayer(c)
+	client := s.budgetClient(c, "https://api.example.com", replayer)
+	_, err := client.GetWallet("personal")
+	c.Assert(err, gc.ErrorMatches, "wallet not found")
+	response, err := client.CreateWallet("personal", "100")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response, gc.Equals, "wallet created")
+	wallet, err := client.GetWallet("personal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(wallet.Limit, gc.Equals, "100")
+	c.Assert(replayer.Remaining(), gc.HasLen, 0)
+
+	_, err = client.GetWallet("personal")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "failed to execute request: recorded interaction for GET /wallet/personal not found")
+}
+
+func (s *cassetteSuite) TestReplayMatchesBody(c *gc.C) {
+	s.record(c, func(h cassette.HTTPClient) {
+		client := s.budgetClient(c, s.server.URL, h)
+		_, err := client.CreateWallet("personal", "100")
+		c.Assert(err, jc.ErrorIsNil)
+	})
+
+	replayer := s.replayer(c)
+	client := s.budgetClient(c, s.server.URL, replayer)
+	_, err := client.CreateWallet("personal", "200")
+	c.Assert(err, gc.ErrorMatches, "failed to execute request: recorded interaction for POST /wallet not found")
+	c.Assert(replayer.Remaining(), gc.HasLen, 1)
+	_, err = client.CreateWallet("personal", "100")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *cassetteSuite) TestScrub(c *gc.C) {
+	modelUUID := utils.MustNewUUID().String()
+	s.record(c, func(h cassette.HTTPClient) {
+		client, err := sla.NewClient(
+			sla.APIRoot(s.server.URL),
+			sla.HTTPClient(h),
+			sla.Authentication(auth.BearerToken("secret")),
+		)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = client.Authorize(modelUUID, "essential", "")
+		c.Assert(err, jc.ErrorIsNil)
+	})
+
+	data, err := ioutil.ReadFile(s.path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Not(jc.Contains), "Bearer secret")
+	c.Assert(string(data), gc.Not(jc.Contains), `"id"`)
+
+	client, err := sla.NewClient(sla.APIRoot("https://api.example.com"), sla.HTTPClient(s.replayer(c)))
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := client.Authorize(modelUUID, "essential", "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Owner, gc.Equals, "bob")
+	// The credentials are replaced by a placeholder macaroon.
+	c.Assert(string(resp.Credentials.Id()), gc.Equals, "REDACTED")
+}
+
+func (s *cassetteSuite) TestScrubBody(c *gc.C) {
+	c.Assert(cassette.Scrub([]byte(`{"uuid":"batch","credentials":"c2VjcmV0","sla-credentials":{"token":["a"]}}`)), gc.Equals,
+		`{"credentials":"REDACTED","sla-credentials":{"token":["REDACTED"]},"uuid":"batch"}`)
+	c.Assert(cassette.Scrub([]byte("not json")), gc.Equals, "not json")
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cassette
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/romulus/api/logging"
+)
+
+// Recorder is an http client that records the requests it sends, and
+// the responses received, to a cassette.
+type Recorder struct {
+	h HTTPClient
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder returns a Recorder that sends requests using h.
+func NewRecorder(h HTTPClient) *Recorder {
+	return &Recorder{h: h}
+}
+
+// Do implements the HTTPClient interface. Requests that fail without a
+// response are not recorded.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	recorded, err := newRequest(req)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot record request")
+	}
+	resp, err := r.h.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	var body []byte
+	if resp.Body != nil {
+		body, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot record response")
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: recorded,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     logging.RedactHeader(resp.Header),
+			Body:       Scrub(body),
+		},
+	})
+	return resp, nil
+}
+
+// Cassette returns a cassette holding the interactions recorded so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Cassette{
+		Interactions: append([]Interaction(nil), r.cassette.Interactions...),
+	}
+}
+
+// Save writes the interactions recorded so far to the file at path.
+func (r *Recorder) Save(path string) error {
+	return errors.Trace(r.Cassette().Save(path))
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/juju/errors"
+)
+
+// Replayer is an http client that responds to requests with the
+// responses recorded in a cassette.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer returns a Replayer that serves the interactions in c.
+func NewReplayer(c *Cassette) *Replayer {
+	return &Replayer{
+		interactions: c.Interactions,
+		used:         make([]bool, len(c.Interactions)),
+	}
+}
+
+// Do implements the HTTPClient interface. A request is matched against
+// the recorded requests on its method, path and query and body, where
+// JSON bodies match if they hold the same values once scrubbed. Each
+// recorded interaction is replayed once, in the order recorded, so
+// repeated requests receive successive responses. If no unused
+// interaction matches, a NotFound error is returned.
+func (r *Replayer) Do(req *http.Request) (*http.Response, error) {
+	recorded, err := newRequest(req)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot replay request")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.interactions {
+		if r.used[i] || !matches(interaction.Request, recorded) {
+			continue
+		}
+		r.used[i] = true
+		resp := interaction.Response
+		header := resp.Header
+		if header == nil {
+			header = make(http.Header)
+		}
+		return &http.Response{
+			Status:     fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+			StatusCode: resp.StatusCode,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     header.Clone(),
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(resp.Body))),
+			Request:    req,
+		}, nil
+	}
+	return nil, errors.NotFoundf("recorded interaction for %s %s", recorded.Method, recorded.Path)
+}
+
+// Remaining returns the interactions that have not been replayed.
+func (r *Replayer) Remaining() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var remaining []Interaction
+	for i, interaction := range r.interactions {
+		if !r.used[i] {
+			remaining = append(remaining, interaction)
+		}
+	}
+	return remaining
+}
+
+// matches returns whether the request matches the recorded request.
+func matches(recorded, req Request) bool {
+	if recorded.Method != req.Method || recorded.Path != req.Path {
+		return false
+	}
+	var recordedBody, reqBody interface{}
+	if json.Unmarshal([]byte(recorded.Body), &recordedBody) != nil || json.Unmarshal([]byte(req.Body), &reqBody) != nil {
+		return recorded.Body == req.Body
+	}
+	return reflect.DeepEqual(recordedBody, reqBody)
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package instrument defines the hook through which the romulus API
+// clients report the requests they send.
+package instrument
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrorStatus is the status reported for requests that failed without
+// a response.
+const ErrorStatus = "error"
+
+// Observer is notified of every request sent by an API client.
+type Observer interface {
+	// ObserveRequest is called when a request completes. The
+	// operation names the client call, such as "budget.CreateWallet",
+	// and status holds the HTTP status code of the response, or
+	// ErrorStatus if no response was received.
+	ObserveRequest(operation, status string, duration time.Duration)
+}
+
+// Observe reports the request that started at start to o, if o is not
+// nil.
+func Observe(o Observer, operation string, start time.Time, resp *http.Response, err error) {
+	if o == nil {
+		return
+	}
+	status := ErrorStatus
+	if err == nil && resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	o.ObserveRequest(operation, status, time.Since(start))
+}
+
+// CacheObserver is implemented by observers that are also notified of
+// the requests an API client serves from its cache. Cache hits are not
+// reported through ObserveRequest, as no request is sent.
+type CacheObserver interface {
+	// ObserveCacheHit is called when the operation is served from
+	// the cache.
+	ObserveCacheHit(operation string)
+}
+
+// CacheHit records that the request was served from the cache: the
+// span started for it by StartSpan is marked with CacheKey, and the
+// hit is reported to o if it implements CacheObserver. The span should
+// then be ended with a nil response and error.
+func CacheHit(o Observer, operation string, req *http.Request) {
+	trace.SpanFromContext(req.Context()).SetAttributes(CacheKey.String("hit"))
+	if co, ok := o.(CacheObserver); ok {
+		co.ObserveCacheHit(operation)
+	}
+}
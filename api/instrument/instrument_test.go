@@ -0,0 +1,67 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instrument_test
+
+import (
+	"errors"
+	"net/http"
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/romulus/api/instrument"
+)
+
+func Test(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type instrumentSuite struct{}
+
+var _ = gc.Suite(&instrumentSuite{})
+
+func (s *instrumentSuite) TestObserve(c *gc.C) {
+	o := &observer{}
+	start := time.Now()
+	instrument.Observe(o, "budget.GetWallet", start, &http.Response{StatusCode: http.StatusNotFound}, nil)
+	instrument.Observe(o, "budget.GetWallet", start, nil, errors.New("connection refused"))
+	c.Assert(o.statuses, jc.DeepEquals, []string{"404", instrument.ErrorStatus})
+}
+
+func (s *instrumentSuite) TestObserveNil(c *gc.C) {
+	// A nil observer is ignored.
+	instrument.Observe(nil, "budget.GetWallet", time.Now(), nil, nil)
+}
+
+func (s *instrumentSuite) TestCacheHit(c *gc.C) {
+	req, err := http.NewRequest("GET", "https://api.example.com/wallet/personal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	o := &observer{}
+	instrument.CacheHit(o, "budget.GetWallet", req)
+	c.Assert(o.statuses, gc.HasLen, 0)
+	c.Assert(o.cacheHits, jc.DeepEquals, []string{"budget.GetWallet"})
+
+	// Observers that do not implement CacheObserver are not notified.
+	instrument.CacheHit(&basicObserver{}, "budget.GetWallet", req)
+	instrument.CacheHit(nil, "budget.GetWallet", req)
+}
+
+type basicObserver struct{}
+
+func (*basicObserver) ObserveRequest(operation, status string, duration time.Duration) {}
+
+type observer struct {
+	statuses  []string
+	cacheHits []string
+}
+
+func (o *observer) ObserveRequest(operation, status string, duration time.Duration) {
+	o.statuses = append(o.statuses, status)
+}
+
+func (o *observer) ObserveCacheHit(operation string) {
+	o.cacheHits = append(o.cacheHits, operation)
+}
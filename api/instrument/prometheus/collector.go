@@ -0,0 +1,80 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package prometheus provides an instrument.Observer that exposes the
+// requests sent by the romulus API clients as Prometheus metrics.
+package prometheus
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/juju/romulus/api/instrument"
+)
+
+var _ instrument.Observer = (*Collector)(nil)
+var _ instrument.CacheObserver = (*Collector)(nil)
+var _ prometheus.Collector = (*Collector)(nil)
+
+// Collector records the number, status and latency of the requests
+// sent by the API clients, and the number of requests served from
+// their caches, labelled by operation. It implements both
+// instrument.Observer and prometheus.Collector, so a single collector
+// can be passed to the clients and registered with a registry.
+type Collector struct {
+	requests  *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+	cacheHits *prometheus.CounterVec
+}
+
+// NewCollector returns a new Collector whose metrics are prefixed with
+// the given namespace.
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "romulus_client",
+			Name:      "requests_total",
+			Help:      "Number of requests sent to the romulus APIs, by operation and status code.",
+		}, []string{"operation", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "romulus_client",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of requests sent to the romulus APIs, by operation.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+		cacheHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "romulus_client",
+			Name:      "cache_hits_total",
+			Help:      "Number of requests served from the client cache without contacting the romulus APIs, by operation.",
+		}, []string{"operation"}),
+	}
+}
+
+// ObserveRequest implements instrument.Observer.
+func (c *Collector) ObserveRequest(operation, status string, duration time.Duration) {
+	c.requests.WithLabelValues(operation, status).Inc()
+	c.duration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// ObserveCacheHit implements instrument.CacheObserver.
+func (c *Collector) ObserveCacheHit(operation string) {
+	c.cacheHits.WithLabelValues(operation).Inc()
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.duration.Describe(ch)
+	c.cacheHits.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.duration.Collect(ch)
+	c.cacheHits.Collect(ch)
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package prometheus_test
+
+import (
+	"strings"
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/romulus/api/instrument"
+	"github.com/juju/romulus/api/instrument/prometheus"
+)
+
+func Test(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type collectorSuite struct{}
+
+var _ = gc.Suite(&collectorSuite{})
+
+func (s *collectorSuite) TestCollect(c *gc.C) {
+	collector := prometheus.NewCollector("juju")
+	collector.ObserveRequest("budget.CreateWallet", "200", 20*time.Millisecond)
+	collector.ObserveRequest("budget.CreateWallet", "200", 30*time.Millisecond)
+	collector.ObserveRequest("budget.CreateWallet", "400", 10*time.Millisecond)
+	collector.ObserveRequest("plan.Authorize", instrument.ErrorStatus, time.Second)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP juju_romulus_client_requests_total Number of requests sent to the romulus APIs, by operation and status code.
+# TYPE juju_romulus_client_requests_total counter
+juju_romulus_client_requests_total{code="200",operation="budget.CreateWallet"} 2
+juju_romulus_client_requests_total{code="400",operation="budget.CreateWallet"} 1
+juju_romulus_client_requests_total{code="error",operation="plan.Authorize"} 1
+`), "juju_romulus_client_requests_total")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(testutil.CollectAndCount(collector, "juju_romulus_client_request_duration_seconds"), gc.Equals, 2)
+}
+
+func (s *collectorSuite) TestCollectCacheHits(c *gc.C) {
+	collector := prometheus.NewCollector("juju")
+	collector.ObserveRequest("budget.GetWallet", "200", 20*time.Millisecond)
+	collector.ObserveCacheHit("budget.GetWallet")
+	collector.ObserveCacheHit("budget.GetWallet")
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP juju_romulus_client_cache_hits_total Number of requests served from the client cache without contacting the romulus APIs, by operation.
+# TYPE juju_romulus_client_cache_hits_total counter
+juju_romulus_client_cache_hits_total{operation="budget.GetWallet"} 2
+`), "juju_romulus_client_cache_hits_total")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testutil.CollectAndCount(collector, "juju_romulus_client_requests_total"), gc.Equals, 1)
+}
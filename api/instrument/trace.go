@@ -0,0 +1,70 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instrument
+
+import (
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the tracer used by the API clients.
+const TracerName = "github.com/juju/romulus"
+
+// Attribute keys set on the spans of API client operations.
+const (
+	OperationKey  = attribute.Key("romulus.operation")
+	ModelKey      = attribute.Key("romulus.model_uuid")
+	WalletKey     = attribute.Key("romulus.wallet")
+	CharmURLKey   = attribute.Key("romulus.charm_url")
+	CacheKey      = attribute.Key("romulus.cache")
+	StatusCodeKey = attribute.Key("http.status_code")
+)
+
+// propagator injects the W3C trace context and baggage headers.
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// StartSpan starts a span for the operation using tp, or the global
+// tracer provider if tp is nil, and injects its trace context into the
+// request headers. It returns the request to send and a function that
+// ends the span with the outcome of the request, or with no outcome if
+// both the response and error are nil because no request was sent.
+// Empty attribute values are omitted.
+func StartSpan(tp trace.TracerProvider, operation string, req *http.Request, attrs ...attribute.KeyValue) (*http.Request, func(*http.Response, error)) {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	spanAttrs := []attribute.KeyValue{OperationKey.String(operation)}
+	for _, attr := range attrs {
+		if attr.Value.Emit() != "" {
+			spanAttrs = append(spanAttrs, attr)
+		}
+	}
+	ctx, span := tp.Tracer(TracerName).Start(req.Context(), operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(spanAttrs...),
+	)
+	req = req.WithContext(ctx)
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	return req, func(resp *http.Response, err error) {
+		defer span.End()
+		if resp == nil && err == nil {
+			return
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return
+		}
+		span.SetAttributes(StatusCodeKey.Int(resp.StatusCode))
+		if resp.StatusCode >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, strconv.Itoa(resp.StatusCode)+" "+http.StatusText(resp.StatusCode))
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instrument_test
+
+import (
+	"errors"
+	"net/http"
+
+	jc "github.com/juju/testing/checkers"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/romulus/api/instrument"
+)
+
+type traceSuite struct {
+	exporter *tracetest.InMemoryExporter
+	provider *sdktrace.TracerProvider
+}
+
+var _ = gc.Suite(&traceSuite{})
+
+func (s *traceSuite) SetUpTest(c *gc.C) {
+	s.exporter = tracetest.NewInMemoryExporter()
+	s.provider = sdktrace.NewTracerProvider(sdktrace.WithSyncer(s.exporter))
+}
+
+func (s *traceSuite) TestStartSpan(c *gc.C) {
+	req, err := http.NewRequest("GET", "https://api.example.com/wallet/personal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	req, end := instrument.StartSpan(s.provider, "budget.GetWallet", req,
+		instrument.WalletKey.String("personal"),
+		instrument.ModelKey.String(""),
+	)
+	c.Assert(req.Header.Get("traceparent"), gc.Matches, "00-[0-9a-f]{32}-[0-9a-f]{16}-01")
+	end(&http.Response{StatusCode: http.StatusOK}, nil)
+
+	spans := s.exporter.GetSpans()
+	c.Assert(spans, gc.HasLen, 1)
+	c.Assert(spans[0].Name, gc.Equals, "budget.GetWallet")
+	c.Assert(spans[0].SpanContext.TraceID().String(), gc.Equals, req.Header.Get("traceparent")[3:35])
+	c.Assert(spans[0].Status.Code, gc.Equals, codes.Unset)
+	c.Assert(attributes(spans[0]), jc.DeepEquals, map[string]string{
+		"romulus.operation": "budget.GetWallet",
+		"romulus.wallet":    "personal",
+		"http.status_code":  "200",
+	})
+}
+
+func (s *traceSuite) TestStartSpanErrorResponse(c *gc.C) {
+	req, err := http.NewRequest("GET", "https://api.example.com/wallet/personal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, end := instrument.StartSpan(s.provider, "budget.GetWallet", req)
+	end(&http.Response{StatusCode: http.StatusNotFound}, nil)
+
+	spans := s.exporter.GetSpans()
+	c.Assert(spans, gc.HasLen, 1)
+	c.Assert(spans[0].Status.Code, gc.Equals, codes.Error)
+	c.Assert(spans[0].Status.Description, gc.Equals, "404 Not Found")
+	c.Assert(attributes(spans[0])["http.status_code"], gc.Equals, "404")
+}
+
+func (s *traceSuite) TestStartSpanError(c *gc.C) {
+	req, err := http.NewRequest("GET", "https://api.example.com/wallet/personal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, end := instrument.StartSpan(s.provider, "budget.GetWallet", req)
+	end(nil, errors.New("connection refused"))
+
+	spans := s.exporter.GetSpans()
+	c.Assert(spans, gc.HasLen, 1)
+	c.Assert(spans[0].Status.Code, gc.Equals, codes.Error)
+	c.Assert(spans[0].Status.Description, gc.Equals, "connection refused")
+	c.Assert(spans[0].Events, gc.HasLen, 1)
+	c.Assert(spans[0].Events[0].Name, gc.Equals, "exception")
+}
+
+func (s *traceSuite) TestStartSpanCacheHit(c *gc.C) {
+	req, err := http.NewRequest("GET", "https://api.example.com/wallet/personal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	req, end := instrument.StartSpan(s.provider, "budget.GetWallet", req)
+	instrument.CacheHit(nil, "budget.GetWallet", req)
+	end(nil, nil)
+
+	spans := s.exporter.GetSpans()
+	c.Assert(spans, gc.HasLen, 1)
+	c.Assert(spans[0].Status.Code, gc.Equals, codes.Unset)
+	c.Assert(attributes(spans[0]), jc.DeepEquals, map[string]string{
+		"romulus.operation": "budget.GetWallet",
+		"romulus.cache":     "hit",
+	})
+}
+
+// attributes returns the attributes of the span keyed by name.
+func attributes(span tracetest.SpanStub) map[string]string {
+	attrs := make(map[string]string)
+	for _, attr := range span.Attributes {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	return attrs
+}
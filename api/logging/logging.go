@@ -0,0 +1,290 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package logging provides structured logging of the requests sent by
+// the romulus API clients. Credentials, macaroons and authorization
+// data are redacted from the logged headers and bodies.
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/loggo"
+	"github.com/juju/utils/v3"
+)
+
+// RequestIDHeader holds the header used to identify a request. It is
+// set on requests that do not already have one.
+const RequestIDHeader = "X-Request-Id"
+
+// Level determines how much of each request is logged.
+type Level int
+
+const (
+	// Basic logs the method, URL, status, duration and request id of
+	// each request.
+	Basic Level = iota
+	// Headers additionally logs the request and response headers.
+	Headers
+	// Bodies additionally logs the request and response bodies.
+	Bodies
+)
+
+// Record holds the details logged for a request.
+type Record struct {
+	// Operation names the client call, such as "budget.CreateWallet".
+	Operation string
+	Method    string
+	URL       string
+	// Status holds the HTTP status code of the response, or 0 if no
+	// response was received.
+	Status    int
+	Duration  time.Duration
+	RequestID string
+	// Error holds the error returned when no response was received.
+	Error string
+
+	// RequestHeader and ResponseHeader are only set at the Headers
+	// level or above.
+	RequestHeader  http.Header
+	ResponseHeader http.Header
+
+	// RequestBody and ResponseBody are only set at the Bodies level.
+	RequestBody  string
+	ResponseBody string
+}
+
+// String returns the record formatted as space separated key=value
+// pairs.
+func (r Record) String() string {
+	var fields []string
+	add := func(key, value string) {
+		if value == "" {
+			return
+		}
+		if strings.ContainsAny(value, " \t\n\"=") {
+			value = strconv.Quote(value)
+		}
+		fields = append(fields, key+"="+value)
+	}
+	add("operation", r.Operation)
+	add("method", r.Method)
+	add("url", r.URL)
+	if r.Status != 0 {
+		add("status", strconv.Itoa(r.Status))
+	}
+	add("duration", r.Duration.String())
+	add("request-id", r.RequestID)
+	add("error", r.Error)
+	add("request-headers", formatHeader(r.RequestHeader))
+	add("response-headers", formatHeader(r.ResponseHeader))
+	add("request-body", r.RequestBody)
+	add("response-body", r.ResponseBody)
+	return strings.Join(fields, " ")
+}
+
+// formatHeader returns the header as a sorted, semicolon separated list.
+func formatHeader(h http.Header) string {
+	var fields []string
+	for name, values := range h {
+		fields = append(fields, name+": "+strings.Join(values, ", "))
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, "; ")
+}
+
+// Logger is passed a record of every request sent by an API client.
+type Logger interface {
+	LogRequest(r Record)
+}
+
+// Loggo returns a Logger that writes records to the given loggo logger
+// at DEBUG level.
+func Loggo(logger loggo.Logger) Logger {
+	return loggoLogger{logger}
+}
+
+type loggoLogger struct {
+	logger loggo.Logger
+}
+
+// LogRequest implements the Logger interface.
+func (l loggoLogger) LogRequest(r Record) {
+	l.logger.Debugf("%s", r)
+}
+
+// HTTPClient defines the interface of the http client used to send
+// logged requests.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Do sends the request using h and logs it to l at the given level.
+// If l is nil the request is sent without being logged. The response
+// body is left unchanged for the caller to read.
+func Do(l Logger, level Level, h HTTPClient, operation string, req *http.Request) (*http.Response, error) {
+	if l == nil {
+		return h.Do(req)
+	}
+	requestID := req.Header.Get(RequestIDHeader)
+	if requestID == "" {
+		requestID = utils.MustNewUUID().String()
+		req.Header.Set(RequestIDHeader, requestID)
+	}
+	r := Record{
+		Operation: operation,
+		Method:    req.Method,
+		URL:       req.URL.String(),
+		RequestID: requestID,
+	}
+	if level >= Headers {
+		r.RequestHeader = RedactHeader(req.Header)
+	}
+	if level >= Bodies && req.GetBody != nil {
+		r.RequestBody = readBody(req.GetBody)
+	}
+
+	start := time.Now()
+	resp, err := h.Do(req)
+	r.Duration = time.Since(start)
+	if err != nil {
+		r.Error = err.Error()
+		l.LogRequest(r)
+		return resp, err
+	}
+	r.Status = resp.StatusCode
+	if id := resp.Header.Get(RequestIDHeader); id != "" {
+		r.RequestID = id
+	}
+	if level >= Headers {
+		r.ResponseHeader = RedactHeader(resp.Header)
+	}
+	if level >= Bodies && resp.Body != nil {
+		data, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+		if err == nil {
+			r.ResponseBody = RedactBody(data)
+		}
+	}
+	l.LogRequest(r)
+	return resp, nil
+}
+
+// readBody returns a redacted copy of the request body.
+func readBody(getBody func() (io.ReadCloser, error)) string {
+	body, err := getBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return ""
+	}
+	return RedactBody(data)
+}
+
+// Redacted replaces redacted values.
+const Redacted = "REDACTED"
+
+// RedactHeader returns a copy of the header with the values of headers
+// that carry credentials replaced.
+func RedactHeader(h http.Header) http.Header {
+	redacted := make(http.Header, len(h))
+	for name, values := range h {
+		if IsSecret(name) || strings.EqualFold(name, "Cookie") || strings.EqualFold(name, "Set-Cookie") {
+			values = []string{Redacted}
+		}
+		redacted[name] = values
+	}
+	return redacted
+}
+
+// RedactBody returns the body with any credentials, macaroons and
+// authorization data replaced. Bodies that are not JSON are not logged,
+// only their length.
+func RedactBody(data []byte) string {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Sprintf("[%d bytes]", len(data))
+	}
+	redacted, err := json.Marshal(redact(v))
+	if err != nil {
+		return fmt.Sprintf("[%d bytes]", len(data))
+	}
+	return string(redacted)
+}
+
+// redact returns v with secret values replaced.
+func redact(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if IsMacaroon(v) {
+			return Redacted
+		}
+		for key, value := range v {
+			if IsSecret(key) {
+				v[key] = Redacted
+			} else {
+				v[key] = redact(value)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redact(value)
+		}
+		return v
+	}
+	return v
+}
+
+// secretWords are the words that mark a header or field as holding a
+// secret.
+var secretWords = []string{"authorization", "credential", "macaroon", "token", "password", "secret"}
+
+// IsSecret returns whether the named header or field holds a secret,
+// judged by the last word of its name, so that "sla-credentials" is
+// secret but "credentials-expiry" is not.
+func IsSecret(name string) bool {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+	if len(words) == 0 {
+		return false
+	}
+	last := strings.TrimSuffix(words[len(words)-1], "s")
+	for _, word := range secretWords {
+		if strings.HasSuffix(last, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMacaroon returns whether the object is a JSON encoded macaroon, in
+// either the version 1 or version 2 format.
+func IsMacaroon(v map[string]interface{}) bool {
+	has := func(keys ...string) bool {
+		for _, key := range keys {
+			if _, ok := v[key]; ok {
+				return true
+			}
+		}
+		return false
+	}
+	return has("identifier", "i", "i64") && has("signature", "s", "s64")
+}
@@ -0,0 +1,226 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/macaroon.v2"
+
+	"github.com/juju/romulus/api/logging"
+	"github.com/juju/romulus/wireformat/metrics"
+	"github.com/juju/romulus/wireformat/sla"
+)
+
+func Test(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type loggingSuite struct {
+	server *httptest.Server
+	// requests holds the bodies of the requests received by the server.
+	requests []string
+	response []byte
+}
+
+var _ = gc.Suite(&loggingSuite{})
+
+func (s *loggingSuite) SetUpTest(c *gc.C) {
+	s.requests = nil
+	s.response = []byte(`{"level":"essential"}`)
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		data, err := ioutil.ReadAll(req.Body)
+		c.Check(err, jc.ErrorIsNil)
+		s.requests = append(s.requests, string(data))
+		w.Header().Set("Set-Cookie", "macaroon-abc=secret")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(s.response)
+	}))
+}
+
+func (s *loggingSuite) TearDownTest(c *gc.C) {
+	s.server.Close()
+}
+
+func (s *loggingSuite) newRequest(c *gc.C, body string) *http.Request {
+	req, err := http.NewRequest("POST", s.server.URL+"/sla/authorize", bytes.NewReader([]byte(body)))
+	c.Assert(err, jc.ErrorIsNil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func (s *loggingSuite) TestDoBasic(c *gc.C) {
+	l := &recorder{}
+	resp, err := logging.Do(l, logging.Basic, s.server.Client(), "sla.Authorize", s.newRequest(c, `{"model":"uuid"}`))
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+
+	c.Assert(l.records, gc.HasLen, 1)
+	r := l.records[0]
+	c.Assert(r.Operation, gc.Equals, "sla.Authorize")
+	c.Assert(r.Method, gc.Equals, "POST")
+	c.Assert(r.URL, gc.Equals, s.server.URL+"/sla/authorize")
+	c.Assert(r.Status, gc.Equals, http.StatusOK)
+	c.Assert(r.RequestID, gc.Matches, "[0-9a-f-]{36}")
+	c.Assert(r.RequestHeader, gc.IsNil)
+	c.Assert(r.RequestBody, gc.Equals, "")
+	c.Assert(r.ResponseBody, gc.Equals, "")
+
+	// The request is sent unchanged, apart from the request id.
+	c.Assert(s.requests, jc.DeepEquals, []string{`{"model":"uuid"}`})
+	data, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, `{"level":"essential"}`)
+}
+
+func (s *loggingSuite) TestDoHeaders(c *gc.C) {
+	l := &recorder{}
+	req := s.newRequest(c, "")
+	req.Header.Set(logging.RequestIDHeader, "request-1")
+	req.Header.Set("Macaroons", "secret")
+	resp, err := logging.Do(l, logging.Headers, s.server.Client(), "sla.Authorize", req)
+	c.Assert(err, jc.ErrorIsNil)
+	resp.Body.Close()
+
+	c.Assert(l.records, gc.HasLen, 1)
+	r := l.records[0]
+	c.Assert(r.RequestID, gc.Equals, "request-1")
+	c.Assert(r.RequestHeader, jc.DeepEquals, http.Header{
+		"Authorization":         {logging.Redacted},
+		"Content-Type":          {"application/json"},
+		"Macaroons":             {logging.Redacted},
+		logging.RequestIDHeader: {"request-1"},
+	})
+	c.Assert(r.ResponseHeader.Get("Set-Cookie"), gc.Equals, logging.Redacted)
+	c.Assert(r.ResponseHeader.Get("Content-Type"), gc.Equals, "application/json")
+	c.Assert(r.ResponseBody, gc.Equals, "")
+	// The request itself is not redacted.
+	c.Assert(req.Header.Get("Authorization"), gc.Equals, "Bearer secret")
+}
+
+func (s *loggingSuite) TestDoBodies(c *gc.C) {
+	m, err := macaroon.New([]byte("key"), []byte("id"), "location", macaroon.LatestVersion)
+	c.Assert(err, jc.ErrorIsNil)
+	s.response, err = json.Marshal(sla.SLAResponse{
+		Owner:       "bob",
+		Credentials: m,
+		Message:     "sla set",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	batch, err := json.Marshal(metrics.MetricBatch{
+		UUID:        "batch-uuid",
+		Credentials: []byte("secret"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	l := &recorder{}
+	resp, err := logging.Do(l, logging.Bodies, s.server.Client(), "sla.Authorize", s.newRequest(c, "["+string(batch)+"]"))
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+
+	c.Assert(l.records, gc.HasLen, 1)
+	var request []map[string]interface{}
+	err = json.Unmarshal([]byte(l.records[0].RequestBody), &request)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(request, gc.HasLen, 1)
+	c.Assert(request[0]["uuid"], gc.Equals, "batch-uuid")
+	c.Assert(request[0]["credentials"], gc.Equals, logging.Redacted)
+	c.Assert(request[0]["sla-credentials"], gc.Equals, logging.Redacted)
+
+	var response map[string]interface{}
+	err = json.Unmarshal([]byte(l.records[0].ResponseBody), &response)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response["owner"], gc.Equals, "bob")
+	c.Assert(response["credentials"], gc.Equals, logging.Redacted)
+
+	// The caller still reads the full response.
+	var slaResp sla.SLAResponse
+	err = json.NewDecoder(resp.Body).Decode(&slaResp)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(slaResp.Credentials.Signature(), jc.DeepEquals, m.Signature())
+}
+
+func (s *loggingSuite) TestDoError(c *gc.C) {
+	l := &recorder{}
+	_, err := logging.Do(l, logging.Bodies, failingClient{}, "sla.Authorize", s.newRequest(c, "{}"))
+	c.Assert(err, gc.ErrorMatches, "connection refused")
+	c.Assert(l.records, gc.HasLen, 1)
+	c.Assert(l.records[0].Status, gc.Equals, 0)
+	c.Assert(l.records[0].Error, gc.Equals, "connection refused")
+	c.Assert(l.records[0].RequestBody, gc.Equals, "{}")
+}
+
+func (s *loggingSuite) TestDoNilLogger(c *gc.C) {
+	req := s.newRequest(c, "{}")
+	resp, err := logging.Do(nil, logging.Bodies, s.server.Client(), "sla.Authorize", req)
+	c.Assert(err, jc.ErrorIsNil)
+	resp.Body.Close()
+	c.Assert(req.Header.Get(logging.RequestIDHeader), gc.Equals, "")
+}
+
+func (s *loggingSuite) TestRedactBody(c *gc.C) {
+	m, err := macaroon.New([]byte("key"), []byte("id"), "location", macaroon.V1)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := json.Marshal(macaroon.Slice{m})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(logging.RedactBody(data), gc.Equals, `["REDACTED"]`)
+	c.Assert(logging.RedactBody([]byte(`{"wallet":{"name":"personal","auth-token":"x"}}`)), gc.Equals, `{"wallet":{"auth-token":"REDACTED","name":"personal"}}`)
+	c.Assert(logging.RedactBody([]byte(`{"credentials-expiry":"2026-10-19T00:00:00Z","authToken":"x"}`)), gc.Equals, `{"authToken":"REDACTED","credentials-expiry":"2026-10-19T00:00:00Z"}`)
+	c.Assert(logging.RedactBody([]byte("not json")), gc.Equals, "[8 bytes]")
+	c.Assert(logging.RedactBody(nil), gc.Equals, "")
+}
+
+func (s *loggingSuite) TestRecordString(c *gc.C) {
+	r := logging.Record{
+		Operation: "budget.GetWallet",
+		Method:    "GET",
+		URL:       "https://api.example.com/wallet/personal",
+		Status:    http.StatusOK,
+		Duration:  12 * time.Millisecond,
+		RequestID: "request-1",
+		RequestHeader: http.Header{
+			"Authorization": {logging.Redacted},
+			"Accept":        {"application/json"},
+		},
+		ResponseBody: `{"limit":"10"}`,
+	}
+	c.Assert(r.String(), gc.Equals, `operation=budget.GetWallet method=GET url=https://api.example.com/wallet/personal status=200 duration=12ms request-id=request-1 request-headers="Accept: application/json; Authorization: REDACTED" response-body="{\"limit\":\"10\"}"`)
+}
+
+func (s *loggingSuite) TestLoggo(c *gc.C) {
+	writer := &loggo.TestWriter{}
+	context := loggo.NewContext(loggo.DEBUG)
+	err := context.AddWriter("test", writer)
+	c.Assert(err, jc.ErrorIsNil)
+	l := logging.Loggo(context.GetLogger("romulus"))
+	l.LogRequest(logging.Record{Operation: "budget.GetWallet", Status: http.StatusNotFound})
+	c.Assert(writer.Log(), gc.HasLen, 1)
+	c.Assert(writer.Log()[0].Level, gc.Equals, loggo.DEBUG)
+	c.Assert(writer.Log()[0].Message, gc.Equals, "operation=budget.GetWallet status=404 duration=0s")
+}
+
+type recorder struct {
+	records []logging.Record
+}
+
+func (r *recorder) LogRequest(record logging.Record) {
+	r.records = append(r.records, record)
+}
+
+type failingClient struct{}
+
+func (failingClient) Do(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
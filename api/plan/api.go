@@ -11,12 +11,20 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/go-macaroon-bakery/macaroon-bakery/v3/httpbakery"
 	"github.com/juju/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gopkg.in/macaroon.v2"
 
 	"github.com/juju/romulus"
+	"github.com/juju/romulus/api/auth"
+	"github.com/juju/romulus/api/cache"
+	"github.com/juju/romulus/api/instrument"
+	"github.com/juju/romulus/api/logging"
+	"github.com/juju/romulus/api/ratelimit"
 	wireformat "github.com/juju/romulus/wireformat/plan"
 )
 
@@ -29,6 +37,8 @@ type Client interface {
 // AuthorizationClient defines the interface available to clients of the public plan api.
 type AuthorizationClient interface {
 	// Authorize returns the authorization macaroon for the specified environment, charm url and service name.
+	// If visitWebPage is not nil it is used to complete any discharge that requires user interaction,
+	// unless the client was created with an explicit Interaction policy, which takes precedence.
 	Authorize(environmentUUID, charmURL, serviceName, plan string, visitWebPage func(*url.URL) error) (*macaroon.Macaroon, error)
 }
 
@@ -42,8 +52,17 @@ type httpClient interface {
 
 // client is the implementation of the Client interface.
 type client struct {
-	client  httpClient
-	apiRoot string
+	client        httpClient
+	apiRoot       string
+	authenticator auth.Authenticator
+	interactor    httpbakery.Interactor
+	bakeryClient  *httpbakery.Client
+	observer      instrument.Observer
+	tracer        trace.TracerProvider
+	logger        logging.Logger
+	logLevel      logging.Level
+	cache         *cache.Cache
+	limiter       *ratelimit.Limiter
 }
 
 // ClientOption defines a function which configures a Client.
@@ -66,6 +85,79 @@ func APIRoot(url string) func(h *client) error {
 	}
 }
 
+// Authentication sets the method used to authenticate API requests.
+// If an http client is also specified, authenticated requests are sent
+// using that client.
+func Authentication(a auth.Authenticator) func(h *client) error {
+	return func(h *client) error {
+		h.authenticator = a
+		return nil
+	}
+}
+
+// Interaction sets the interaction policy used by the default bakery
+// client when a discharge requires user interaction (see
+// auth.Interactive, auth.Polling and auth.NonInteractive). The policy
+// takes precedence over the visitWebPage function passed to Authorize.
+// It has no effect if an http client or authentication method is
+// specified.
+func Interaction(i httpbakery.Interactor) func(h *client) error {
+	return func(h *client) error {
+		h.interactor = i
+		return nil
+	}
+}
+
+// Instrument sets the observer that is notified of every request sent
+// by the client. Operations are named after the client method, such as
+// "plan.Authorize".
+func Instrument(o instrument.Observer) func(h *client) error {
+	return func(h *client) error {
+		h.observer = o
+		return nil
+	}
+}
+
+// TracerProvider sets the tracer provider used to trace the requests
+// sent by the client. If it is not set the global tracer provider is
+// used.
+func TracerProvider(tp trace.TracerProvider) func(h *client) error {
+	return func(h *client) error {
+		h.tracer = tp
+		return nil
+	}
+}
+
+// Logging sets the logger passed a record of every request sent by the
+// client. The level determines whether headers and bodies are logged;
+// credentials, macaroons and authorization data are always redacted.
+func Logging(l logging.Logger, level logging.Level) func(h *client) error {
+	return func(h *client) error {
+		h.logger = l
+		h.logLevel = level
+		return nil
+	}
+}
+
+// Cache sets the cache used to serve repeated read requests. Requests
+// that change the service's state invalidate the cached responses to
+// plan operations.
+func Cache(rc *cache.Cache) func(h *client) error {
+	return func(h *client) error {
+		h.cache = rc
+		return nil
+	}
+}
+
+// RateLimit sets the limiter that paces the requests sent by the
+// client. The same limiter may be shared with other clients.
+func RateLimit(l *ratelimit.Limiter) func(h *client) error {
+	return func(h *client) error {
+		h.limiter = l
+		return nil
+	}
+}
+
 // NewAuthorizationClient returns a new public authorization client.
 func NewAuthorizationClient(options ...ClientOption) (AuthorizationClient, error) {
 	return NewClient(options...)
@@ -74,7 +166,6 @@ func NewAuthorizationClient(options ...ClientOption) (AuthorizationClient, error
 // NewClient returns a new client for plan management.
 func NewClient(options ...ClientOption) (*client, error) {
 	c := &client{
-		client:  httpbakery.NewClient(),
 		apiRoot: romulus.DefaultAPIRoot,
 	}
 
@@ -85,6 +176,21 @@ func NewClient(options ...ClientOption) (*client, error) {
 		}
 	}
 
+	if c.authenticator == nil && c.client == nil {
+		c.bakeryClient = httpbakery.NewClient()
+		if c.interactor != nil {
+			c.bakeryClient.AddInteractor(c.interactor)
+		}
+		c.authenticator = auth.Bakery(c.bakeryClient)
+	}
+	if c.authenticator != nil {
+		h, err := auth.NewClient(c.authenticator, c.client)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		c.client = h
+	}
+
 	return c, nil
 }
 
@@ -102,7 +208,9 @@ func (c *client) GetAssociatedPlans(charmURL string) ([]wireformat.Plan, error)
 	if err != nil {
 		return nil, errors.Annotate(err, "failed to create GET request")
 	}
-	response, err := c.client.Do(req)
+	response, err := c.send(c.client, "plan.GetAssociatedPlans", req,
+		instrument.CharmURLKey.String(charmURL),
+	)
 	if err != nil {
 		return nil, errors.Annotate(err, "failed to retrieve associated plans")
 	}
@@ -151,7 +259,14 @@ func (c *client) Authorize(environmentUUID, charmURL, serviceName, planURL strin
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	response, err := c.client.Do(req)
+	client, err := c.interactiveClient(visitWebPage)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	response, err := c.send(client, "plan.Authorize", req,
+		instrument.ModelKey.String(environmentUUID),
+		instrument.CharmURLKey.String(charmURL),
+	)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -175,6 +290,48 @@ func (c *client) Authorize(environmentUUID, charmURL, serviceName, planURL strin
 	return m, nil
 }
 
+// send sends the request using h in a span with the given attributes,
+// or serves it from the cache, reporting it to the observer and logger
+// if they are set. Cache hits are reported to the observer separately
+// from the requests sent.
+func (c *client) send(h httpClient, operation string, req *http.Request, attrs ...attribute.KeyValue) (*http.Response, error) {
+	req, end := instrument.StartSpan(c.tracer, operation, req, attrs...)
+	sent := false
+	resp, err := c.cache.Do(operation, req, func(req *http.Request) (*http.Response, error) {
+		sent = true
+		start := time.Now()
+		resp, err := c.limiter.Do(req, func(req *http.Request) (*http.Response, error) {
+			return logging.Do(c.logger, c.logLevel, h, operation, req)
+		})
+		instrument.Observe(c.observer, operation, start, resp, err)
+		return resp, err
+	})
+	if req.Method != "GET" {
+		c.cache.Invalidate("plan.")
+	}
+	if sent {
+		end(resp, err)
+	} else {
+		instrument.CacheHit(c.observer, operation, req)
+		end(nil, nil)
+	}
+	return resp, err
+}
+
+// interactiveClient returns the http client used to send a request
+// that should complete any required interaction by calling visitWebPage.
+// If visitWebPage is nil, the client has an explicit interaction policy,
+// or the client does not use the default bakery client, the client's
+// own interaction policy is used instead.
+func (c *client) interactiveClient(visitWebPage func(*url.URL) error) (httpClient, error) {
+	if visitWebPage == nil || c.interactor != nil || c.bakeryClient == nil {
+		return c.client, nil
+	}
+	bakeryClient := *c.bakeryClient
+	bakeryClient.InteractionMethods = []httpbakery.Interactor{auth.Interactive(visitWebPage)}
+	return auth.NewClient(auth.Bakery(&bakeryClient), nil)
+}
+
 // discardClose reads any remaining data from the response body and closes it.
 func discardClose(response *http.Response) {
 	if response == nil || response.Body == nil {
@@ -5,16 +5,29 @@ package plan_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
 
+	"github.com/go-macaroon-bakery/macaroon-bakery/v3/bakery"
+	"github.com/go-macaroon-bakery/macaroon-bakery/v3/bakery/checkers"
+	"github.com/go-macaroon-bakery/macaroon-bakery/v3/bakerytest"
+	"github.com/go-macaroon-bakery/macaroon-bakery/v3/httpbakery"
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/v3"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/macaroon.v2"
 
+	"github.com/juju/romulus/api/auth"
+	"github.com/juju/romulus/api/cache"
 	api "github.com/juju/romulus/api/plan"
 	wireformat "github.com/juju/romulus/wireformat/plan"
 )
@@ -136,6 +149,171 @@ func (s *clientSuite) TestAuthorize(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *clientSuite) TestAuthorizeInteraction(c *gc.C) {
+	d, server := newDischargeRequiredServer(c)
+	defer d.Close()
+	defer server.Close()
+
+	client, err := api.NewClient(api.APIRoot(server.URL), api.Interaction(auth.NonInteractive()))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.Authorize(utils.MustNewUUID().String(), "cs:trusty/test-charm-0", "test-charm", "bob/uptime", nil)
+	c.Assert(err, gc.ErrorMatches, `interaction required: visit .*/visit`)
+	c.Assert(auth.IsInteractionRequired(err), jc.IsTrue)
+	c.Assert(errors.Cause(err).(*auth.InteractionRequiredError).URL.String(), gc.Equals, d.Location()+"/visit")
+
+	// The explicit policy takes precedence over visitWebPage.
+	visited := 0
+	_, err = client.Authorize(utils.MustNewUUID().String(), "cs:trusty/test-charm-0", "test-charm", "bob/uptime", func(u *url.URL) error {
+		visited++
+		return nil
+	})
+	c.Assert(auth.IsInteractionRequired(err), jc.IsTrue)
+	c.Assert(visited, gc.Equals, 0)
+}
+
+func (s *clientSuite) TestAuthorizeVisitWebPage(c *gc.C) {
+	d, server := newDischargeRequiredServer(c)
+	defer d.Close()
+	defer server.Close()
+
+	client, err := api.NewClient(api.APIRoot(server.URL))
+	c.Assert(err, jc.ErrorIsNil)
+	var visited []string
+	m, err := client.Authorize(utils.MustNewUUID().String(), "cs:trusty/test-charm-0", "test-charm", "bob/uptime", func(u *url.URL) error {
+		visited = append(visited, u.String())
+		return nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.DeepEquals, []byte("plan"))
+	c.Assert(visited, jc.DeepEquals, []string{d.Location() + "/visit"})
+}
+
+// newDischargeRequiredServer returns a discharger that requires
+// browser-window interaction and a plan service that requires a
+// discharge from it.
+func newDischargeRequiredServer(c *gc.C) (*bakerytest.Discharger, *httptest.Server) {
+	d := bakerytest.NewDischarger(nil)
+	d.Checker = httpbakery.ThirdPartyCaveatCheckerFunc(func(ctx context.Context, req *http.Request, cav *bakery.ThirdPartyCaveatInfo, token *httpbakery.DischargeToken) ([]checkers.Caveat, error) {
+		if token != nil {
+			return nil, nil
+		}
+		err := httpbakery.NewInteractionRequiredError(nil, req)
+		httpbakery.SetWebBrowserInteraction(err, d.Location()+"/visit", d.Location()+"/wait")
+		return nil, err
+	})
+	d.Mux.HandlerFunc("GET", "/wait", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(httpbakery.WaitTokenResponse{
+			Kind:  httpbakery.WebBrowserInteractionKind,
+			Token: "ok",
+		})
+	})
+
+	b := bakery.New(bakery.BakeryParams{
+		Location: "omnibus",
+		Locator:  d,
+		Key:      bakery.MustGenerateKey(),
+	})
+	op := bakery.Op{Entity: "plan", Action: "authorize"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, err := b.Checker.Auth(httpbakery.RequestMacaroons(req)...).Allow(req.Context(), op)
+		if err == nil {
+			m, err := macaroon.New(nil, []byte("plan"), "", macaroon.LatestVersion)
+			c.Check(err, jc.ErrorIsNil)
+			json.NewEncoder(w).Encode(m)
+			return
+		}
+		m, err := b.Oven.NewMacaroon(req.Context(), bakery.LatestVersion, []checkers.Caveat{{
+			Location:  d.Location(),
+			Condition: "is-authenticated-user",
+		}}, op)
+		c.Check(err, jc.ErrorIsNil)
+		httpbakery.WriteError(req.Context(), w, httpbakery.NewDischargeRequiredError(httpbakery.DischargeRequiredErrorParams{
+			Macaroon: m,
+			Request:  req,
+		}))
+	}))
+	return d, server
+}
+
+func (s *clientSuite) TestInstrument(c *gc.C) {
+	observer := &mockObserver{}
+	client, err := api.NewClient(api.HTTPClient(s.httpClient), api.Instrument(observer))
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.httpClient.status = http.StatusNotFound
+	_, err = client.GetAssociatedPlans("bob/uptime")
+	c.Assert(err, gc.NotNil)
+
+	m, err := macaroon.New(nil, nil, "", macaroon.LatestVersion)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := json.Marshal(m)
+	c.Assert(err, jc.ErrorIsNil)
+	s.httpClient.status = http.StatusOK
+	s.httpClient.body = data
+	_, err = client.Authorize(utils.MustNewUUID().String(), "cs:trusty/test-charm-0", "test-charm", utils.MustNewUUID().String(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(observer.requests, jc.DeepEquals, []string{
+		"plan.GetAssociatedPlans 404",
+		"plan.Authorize 200",
+	})
+}
+
+func (s *clientSuite) TestTracing(c *gc.C) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	client, err := api.NewClient(api.HTTPClient(s.httpClient), api.TracerProvider(provider))
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.httpClient.status = http.StatusOK
+	s.httpClient.body = []byte("[]")
+	_, err = client.GetAssociatedPlans("bob/uptime")
+	c.Assert(err, jc.ErrorIsNil)
+
+	m, err := macaroon.New(nil, nil, "", macaroon.LatestVersion)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := json.Marshal(m)
+	c.Assert(err, jc.ErrorIsNil)
+	s.httpClient.body = data
+	modelUUID := utils.MustNewUUID().String()
+	_, err = client.Authorize(modelUUID, "cs:trusty/test-charm-0", "test-charm", "bob/uptime", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	spans := exporter.GetSpans()
+	c.Assert(spans, gc.HasLen, 2)
+	c.Assert(spans[0].Name, gc.Equals, "plan.GetAssociatedPlans")
+	c.Assert(spanAttributes(spans[0]), jc.DeepEquals, map[string]string{
+		"romulus.operation": "plan.GetAssociatedPlans",
+		"romulus.charm_url": "bob/uptime",
+		"http.status_code":  "200",
+	})
+	c.Assert(spans[1].Name, gc.Equals, "plan.Authorize")
+	c.Assert(spanAttributes(spans[1]), jc.DeepEquals, map[string]string{
+		"romulus.operation":  "plan.Authorize",
+		"romulus.model_uuid": modelUUID,
+		"romulus.charm_url":  "cs:trusty/test-charm-0",
+		"http.status_code":   "200",
+	})
+}
+
+func (s *clientSuite) TestCache(c *gc.C) {
+	rc, err := cache.New(cache.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+	client, err := api.NewClient(api.HTTPClient(s.httpClient), api.Cache(rc))
+	c.Assert(err, jc.ErrorIsNil)
+	s.httpClient.status = http.StatusOK
+	s.httpClient.body = []byte(`[{"url":"bob/uptime"}]`)
+	for i := 0; i < 2; i++ {
+		plans, err := client.GetAssociatedPlans("cs:trusty/test-charm-0")
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(plans, gc.HasLen, 1)
+	}
+	_, err = client.GetAssociatedPlans("cs:trusty/other-charm-0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.httpClient.CheckCallNames(c, "Do", "Do")
+}
+
 type mockHttpClient struct {
 	testing.Stub
 
@@ -154,3 +332,20 @@ func (m *mockHttpClient) Do(req *http.Request) (*http.Response, error) {
 		Body:       ioutil.NopCloser(bytes.NewReader(m.body)),
 	}, nil
 }
+
+type mockObserver struct {
+	requests []string
+}
+
+func (o *mockObserver) ObserveRequest(operation, status string, duration time.Duration) {
+	o.requests = append(o.requests, operation+" "+status)
+}
+
+// spanAttributes returns the attributes of the span keyed by name.
+func spanAttributes(span tracetest.SpanStub) map[string]string {
+	attrs := make(map[string]string)
+	for _, attr := range span.Attributes {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	return attrs
+}
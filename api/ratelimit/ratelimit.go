@@ -0,0 +1,208 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package ratelimit limits the rate and concurrency of the requests
+// sent by the romulus API clients.
+package ratelimit
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+)
+
+const (
+	// DefaultMaxRetries holds the number of times a rate limited
+	// request is retried by default.
+	DefaultMaxRetries = 3
+
+	// DefaultRetryAfter holds the time waited before retrying a rate
+	// limited request whose response has no Retry-After header.
+	DefaultRetryAfter = time.Second
+)
+
+// Config holds the configuration of a Limiter.
+type Config struct {
+	// Rate holds the number of requests allowed per second. If it is
+	// zero the rate is not limited.
+	Rate float64
+	// Burst holds the number of requests that may be sent at once
+	// before the rate is limited. It defaults to 1.
+	Burst int
+	// MaxInFlight holds the number of requests that may be in flight
+	// at once. If it is zero the number is not limited.
+	MaxInFlight int
+	// MaxRetries holds the number of times a request is retried after
+	// a 429 Too Many Requests response. It defaults to
+	// DefaultMaxRetries; a negative value disables retries.
+	MaxRetries int
+	// Clock is used to wait for tokens and retries. It defaults to
+	// the wall clock.
+	Clock clock.Clock
+}
+
+// Validate checks the Config for errors.
+func (config Config) Validate() error {
+	if config.Rate < 0 {
+		return errors.NotValidf("negative Rate")
+	}
+	if config.Burst < 0 {
+		return errors.NotValidf("negative Burst")
+	}
+	if config.MaxInFlight < 0 {
+		return errors.NotValidf("negative MaxInFlight")
+	}
+	return nil
+}
+
+// Limiter limits the requests sent through it with a token bucket
+// and a cap on the number of requests in flight. A single Limiter may
+// be shared by several clients so that they are limited together.
+type Limiter struct {
+	config   Config
+	inFlight chan struct{}
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// New returns a new Limiter with the given configuration.
+func New(config Config) (*Limiter, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if config.Burst == 0 {
+		config.Burst = 1
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = DefaultMaxRetries
+	}
+	if config.Clock == nil {
+		config.Clock = clock.WallClock
+	}
+	l := &Limiter{
+		config: config,
+		tokens: float64(config.Burst),
+		last:   config.Clock.Now(),
+	}
+	if config.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, config.MaxInFlight)
+	}
+	return l, nil
+}
+
+// Do sends the request using do once the rate limit and the number of
+// requests in flight allow. Requests that receive a 429 Too Many
+// Requests response are retried after the time given by the response's
+// Retry-After header, if their body can be resent. If l is nil the
+// request is sent immediately.
+func (l *Limiter) Do(req *http.Request, do func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	if l == nil {
+		return do(req)
+	}
+	for retries := 0; ; retries++ {
+		resp, err := l.send(req, do)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+		if retries >= l.config.MaxRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+		wait := l.retryAfter(resp)
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		if err := l.wait(req, wait); err != nil {
+			return nil, errors.Trace(err)
+		}
+		req, err = rewind(req)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+}
+
+// send sends the request once a token and an in-flight slot are
+// available.
+func (l *Limiter) send(req *http.Request, do func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	if err := l.wait(req, l.reserve()); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+		case <-req.Context().Done():
+			return nil, errors.Trace(req.Context().Err())
+		}
+		defer func() { <-l.inFlight }()
+	}
+	return do(req)
+}
+
+// reserve takes a token from the bucket and returns the time to wait
+// until it is available.
+func (l *Limiter) reserve() time.Duration {
+	if l.config.Rate == 0 {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.config.Clock.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.config.Rate
+	if burst := float64(l.config.Burst); l.tokens > burst {
+		l.tokens = burst
+	}
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.config.Rate * float64(time.Second))
+}
+
+// wait waits for d, or until the request is cancelled.
+func (l *Limiter) wait(req *http.Request, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-l.config.Clock.After(d):
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// retryAfter returns the time to wait before retrying the request that
+// received the response.
+func (l *Limiter) retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return t.Sub(l.config.Clock.Now())
+	}
+	return DefaultRetryAfter
+}
+
+// rewind returns a copy of the request with its body reset so that it
+// can be sent again.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req = req.Clone(req.Context())
+	req.Body = body
+	return req, nil
+}
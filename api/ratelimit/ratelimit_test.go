@@ -0,0 +1,225 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ratelimit_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/romulus/api/ratelimit"
+)
+
+func Test(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type limiterSuite struct {
+	clock *testclock.Clock
+}
+
+var _ = gc.Suite(&limiterSuite{})
+
+func (s *limiterSuite) SetUpTest(c *gc.C) {
+	s.clock = testclock.NewClock(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC))
+}
+
+func (s *limiterSuite) newLimiter(c *gc.C, config ratelimit.Config) *ratelimit.Limiter {
+	config.Clock = s.clock
+	l, err := ratelimit.New(config)
+	c.Assert(err, jc.ErrorIsNil)
+	return l
+}
+
+func newRequest(c *gc.C, body string) *http.Request {
+	req, err := http.NewRequest("POST", "https://api.example.com/model/uuid/budget", bytes.NewReader([]byte(body)))
+	c.Assert(err, jc.ErrorIsNil)
+	return req
+}
+
+func response(status int, header http.Header) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+	}
+}
+
+func ok(req *http.Request) (*http.Response, error) {
+	return response(http.StatusOK, nil), nil
+}
+
+type result struct {
+	resp *http.Response
+	err  error
+}
+
+// start sends the request through the limiter in the background.
+func start(l *ratelimit.Limiter, req *http.Request, do func(*http.Request) (*http.Response, error)) <-chan result {
+	done := make(chan result, 1)
+	go func() {
+		resp, err := l.Do(req, do)
+		done <- result{resp, err}
+	}()
+	return done
+}
+
+func assertPending(c *gc.C, done <-chan result) {
+	select {
+	case <-done:
+		c.Fatalf("request completed unexpectedly")
+	case <-time.After(testing.ShortWait):
+	}
+}
+
+func assertDone(c *gc.C, done <-chan result) result {
+	select {
+	case r := <-done:
+		return r
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for request")
+	}
+	panic("unreachable")
+}
+
+func (s *limiterSuite) TestRate(c *gc.C) {
+	l := s.newLimiter(c, ratelimit.Config{Rate: 1, Burst: 2})
+	for i := 0; i < 2; i++ {
+		_, err := l.Do(newRequest(c, "{}"), ok)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	done := start(l, newRequest(c, "{}"), ok)
+	assertPending(c, done)
+	err := s.clock.WaitAdvance(time.Second, testing.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	r := assertDone(c, done)
+	c.Assert(r.err, jc.ErrorIsNil)
+}
+
+func (s *limiterSuite) TestMaxInFlight(c *gc.C) {
+	l := s.newLimiter(c, ratelimit.Config{MaxInFlight: 1})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	first := start(l, newRequest(c, "{}"), func(req *http.Request) (*http.Response, error) {
+		close(started)
+		<-release
+		return ok(req)
+	})
+	<-started
+	sent := make(chan struct{}, 1)
+	second := start(l, newRequest(c, "{}"), func(req *http.Request) (*http.Response, error) {
+		sent <- struct{}{}
+		return ok(req)
+	})
+	assertPending(c, second)
+	c.Assert(sent, gc.HasLen, 0)
+
+	close(release)
+	assertDone(c, first)
+	r := assertDone(c, second)
+	c.Assert(r.err, jc.ErrorIsNil)
+	c.Assert(sent, gc.HasLen, 1)
+}
+
+func (s *limiterSuite) TestRetryAfter(c *gc.C) {
+	l := s.newLimiter(c, ratelimit.Config{})
+	var bodies []string
+	statuses := []int{http.StatusTooManyRequests, http.StatusOK}
+	done := start(l, newRequest(c, `{"limit":"10"}`), func(req *http.Request) (*http.Response, error) {
+		data, err := ioutil.ReadAll(req.Body)
+		c.Check(err, jc.ErrorIsNil)
+		bodies = append(bodies, string(data))
+		status := statuses[0]
+		statuses = statuses[1:]
+		return response(status, http.Header{"Retry-After": {"2"}}), nil
+	})
+	err := s.clock.WaitAdvance(time.Second, testing.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	assertPending(c, done)
+	s.clock.Advance(time.Second)
+	r := assertDone(c, done)
+	c.Assert(r.err, jc.ErrorIsNil)
+	c.Assert(r.resp.StatusCode, gc.Equals, http.StatusOK)
+	// The body is resent.
+	c.Assert(bodies, jc.DeepEquals, []string{`{"limit":"10"}`, `{"limit":"10"}`})
+}
+
+func (s *limiterSuite) TestRetryAfterDate(c *gc.C) {
+	l := s.newLimiter(c, ratelimit.Config{})
+	retryAt := s.clock.Now().Add(5 * time.Second).Format(http.TimeFormat)
+	statuses := []int{http.StatusTooManyRequests, http.StatusOK}
+	done := start(l, newRequest(c, "{}"), func(req *http.Request) (*http.Response, error) {
+		status := statuses[0]
+		statuses = statuses[1:]
+		return response(status, http.Header{"Retry-After": {retryAt}}), nil
+	})
+	err := s.clock.WaitAdvance(5*time.Second, testing.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	r := assertDone(c, done)
+	c.Assert(r.err, jc.ErrorIsNil)
+	c.Assert(r.resp.StatusCode, gc.Equals, http.StatusOK)
+}
+
+func (s *limiterSuite) TestMaxRetries(c *gc.C) {
+	l := s.newLimiter(c, ratelimit.Config{MaxRetries: 1})
+	calls := 0
+	done := start(l, newRequest(c, "{}"), func(req *http.Request) (*http.Response, error) {
+		calls++
+		return response(http.StatusTooManyRequests, nil), nil
+	})
+	err := s.clock.WaitAdvance(ratelimit.DefaultRetryAfter, testing.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	r := assertDone(c, done)
+	c.Assert(r.err, jc.ErrorIsNil)
+	c.Assert(r.resp.StatusCode, gc.Equals, http.StatusTooManyRequests)
+	c.Assert(calls, gc.Equals, 2)
+}
+
+func (s *limiterSuite) TestNoRetries(c *gc.C) {
+	l := s.newLimiter(c, ratelimit.Config{MaxRetries: -1})
+	resp, err := l.Do(newRequest(c, "{}"), func(req *http.Request) (*http.Response, error) {
+		return response(http.StatusTooManyRequests, nil), nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusTooManyRequests)
+}
+
+func (s *limiterSuite) TestCancelled(c *gc.C) {
+	l := s.newLimiter(c, ratelimit.Config{Rate: 1})
+	_, err := l.Do(newRequest(c, "{}"), ok)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := start(l, newRequest(c, "{}").WithContext(ctx), ok)
+	assertPending(c, done)
+	cancel()
+	r := assertDone(c, done)
+	c.Assert(r.err, gc.ErrorMatches, "context canceled")
+}
+
+func (s *limiterSuite) TestNilLimiter(c *gc.C) {
+	var l *ratelimit.Limiter
+	resp, err := l.Do(newRequest(c, "{}"), func(req *http.Request) (*http.Response, error) {
+		return response(http.StatusTooManyRequests, nil), nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusTooManyRequests)
+}
+
+func (s *limiterSuite) TestConfigValidate(c *gc.C) {
+	_, err := ratelimit.New(ratelimit.Config{Rate: -1})
+	c.Assert(err, gc.ErrorMatches, "negative Rate not valid")
+	_, err = ratelimit.New(ratelimit.Config{Burst: -1})
+	c.Assert(err, gc.ErrorMatches, "negative Burst not valid")
+	_, err = ratelimit.New(ratelimit.Config{MaxInFlight: -1})
+	c.Assert(err, gc.ErrorMatches, "negative MaxInFlight not valid")
+}
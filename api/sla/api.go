@@ -9,13 +9,25 @@ import (
 	"encoding/json"
 	"io"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/go-macaroon-bakery/macaroon-bakery/v3/httpbakery"
 	"github.com/juju/errors"
+	"github.com/juju/utils/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/juju/romulus"
+	"github.com/juju/romulus/api/auth"
+	"github.com/juju/romulus/api/cache"
+	"github.com/juju/romulus/api/instrument"
+	"github.com/juju/romulus/api/logging"
+	"github.com/juju/romulus/api/ratelimit"
+	"github.com/juju/romulus/wireformat/budget"
 	"github.com/juju/romulus/wireformat/common"
 	"github.com/juju/romulus/wireformat/sla"
 )
@@ -30,7 +42,32 @@ type AuthClient interface {
 	Authorize(modelUUID, supportLevel, budget string) (*sla.SLAResponse, error)
 }
 
+// Client defines the interface available to clients of the sla api.
+type Client interface {
+	AuthClient
+
+	// GetSLA returns the sla currently set for the specified model.
+	GetSLA(modelUUID string) (*sla.SLA, error)
+
+	// ListLevels returns the support levels available to the user.
+	ListLevels() ([]sla.SupportLevel, error)
+
+	// Unset removes the sla set for the specified model.
+	Unset(modelUUID string) error
+}
+
 var _ AuthClient = (*client)(nil)
+var _ Client = (*client)(nil)
+
+// BudgetClient defines the budget api calls used to resolve the budget
+// of an sla.
+type BudgetClient interface {
+	// ListWallets lists the wallets belonging to the current user.
+	ListWallets() (*budget.ListWalletsResponse, error)
+
+	// GetWallet returns the information of a particular wallet.
+	GetWallet(wallet string) (*budget.WalletWithBudgets, error)
+}
 
 type httpClient interface {
 	Do(req *http.Request) (*http.Response, error)
@@ -38,8 +75,18 @@ type httpClient interface {
 
 // client is the implementation of the Client interface.
 type client struct {
-	client  httpClient
-	apiRoot string
+	client        httpClient
+	apiRoot       string
+	authenticator auth.Authenticator
+	interactor    httpbakery.Interactor
+	bakeryClient  *httpbakery.Client
+	budgetClient  BudgetClient
+	observer      instrument.Observer
+	tracer        trace.TracerProvider
+	logger        logging.Logger
+	logLevel      logging.Level
+	cache         *cache.Cache
+	limiter       *ratelimit.Limiter
 }
 
 // ClientOption defines a function which configures a Client.
@@ -62,10 +109,91 @@ func APIRoot(apiRoot string) func(h *client) error {
 	}
 }
 
+// Authentication sets the method used to authenticate API requests.
+// If an http client is also specified, authenticated requests are sent
+// using that client.
+func Authentication(a auth.Authenticator) func(h *client) error {
+	return func(h *client) error {
+		h.authenticator = a
+		return nil
+	}
+}
+
+// Interaction sets the interaction policy used by the default bakery
+// client when a discharge requires user interaction (see
+// auth.Interactive, auth.Polling and auth.NonInteractive). It has no
+// effect if an http client or authentication method is specified.
+func Interaction(i httpbakery.Interactor) func(h *client) error {
+	return func(h *client) error {
+		h.interactor = i
+		return nil
+	}
+}
+
+// ResolveBudgets sets the budget client used to check that the wallet
+// named in the budget passed to Authorize exists and has enough
+// unallocated funds for the budget limit. If the budget does not name
+// a wallet, the user's default wallet is used.
+func ResolveBudgets(b BudgetClient) func(h *client) error {
+	return func(h *client) error {
+		h.budgetClient = b
+		return nil
+	}
+}
+
+// Instrument sets the observer that is notified of every request sent
+// by the client. Operations are named after the client method, such as
+// "sla.Authorize".
+func Instrument(o instrument.Observer) func(h *client) error {
+	return func(h *client) error {
+		h.observer = o
+		return nil
+	}
+}
+
+// TracerProvider sets the tracer provider used to trace the requests
+// sent by the client. If it is not set the global tracer provider is
+// used.
+func TracerProvider(tp trace.TracerProvider) func(h *client) error {
+	return func(h *client) error {
+		h.tracer = tp
+		return nil
+	}
+}
+
+// Logging sets the logger passed a record of every request sent by the
+// client. The level determines whether headers and bodies are logged;
+// credentials, macaroons and authorization data are always redacted.
+func Logging(l logging.Logger, level logging.Level) func(h *client) error {
+	return func(h *client) error {
+		h.logger = l
+		h.logLevel = level
+		return nil
+	}
+}
+
+// Cache sets the cache used to serve repeated read requests. Requests
+// that change the service's state invalidate the cached responses to
+// sla operations.
+func Cache(rc *cache.Cache) func(h *client) error {
+	return func(h *client) error {
+		h.cache = rc
+		return nil
+	}
+}
+
+// RateLimit sets the limiter that paces the requests sent by the
+// client. The same limiter may be shared with other clients.
+func RateLimit(l *ratelimit.Limiter) func(h *client) error {
+	return func(h *client) error {
+		h.limiter = l
+		return nil
+	}
+}
+
 // NewClient returns a new client for the sla api.
 func NewClient(options ...ClientOption) (*client, error) {
 	c := &client{
-		client:  httpbakery.NewClient(),
 		apiRoot: romulus.DefaultAPIRoot,
 	}
 
@@ -76,58 +204,224 @@ func NewClient(options ...ClientOption) (*client, error) {
 		}
 	}
 
+	if c.authenticator == nil && c.client == nil {
+		c.bakeryClient = httpbakery.NewClient()
+		if c.interactor != nil {
+			c.bakeryClient.AddInteractor(c.interactor)
+		}
+		c.authenticator = auth.Bakery(c.bakeryClient)
+	}
+	if c.authenticator != nil {
+		h, err := auth.NewClient(c.authenticator, c.client)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		c.client = h
+	}
+
 	return c, nil
 }
 
-// Authorize obtains an sla authorization.
+// Authorize obtains an sla authorization. Unknown support levels and
+// invalid model UUIDs are rejected without contacting the service.
+// If budgets are resolved (see ResolveBudgets), the budget is validated
+// against the budget api before the sla is authorized.
 func (c *client) Authorize(modelUUID, supportLevel, budget string) (*sla.SLAResponse, error) {
-	u, err := url.Parse(c.apiRoot + "/sla/authorize")
+	level, err := sla.ParseLevel(supportLevel)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-
 	slaRequest := sla.SLARequest{
 		ModelUUID: modelUUID,
-		Level:     supportLevel,
+		Level:     level,
 		Budget:    budget,
 	}
+	if err := slaRequest.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var resolved *sla.BudgetAssociation
+	if c.budgetClient != nil && budget != "" {
+		resolved, err = c.resolveBudget(budget)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		slaRequest.Budget = resolved.String()
+	}
+	var respDoc sla.SLAResponse
+	err = c.doRequest("sla.Authorize", "POST", "/sla/authorize", slaRequest, &respDoc,
+		instrument.ModelKey.String(modelUUID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if respDoc.Budget == nil {
+		respDoc.Budget = resolved
+	}
+	return &respDoc, nil
+}
 
-	buff := &bytes.Buffer{}
-	encoder := json.NewEncoder(buff)
-	err = encoder.Encode(slaRequest)
+// resolveBudget returns the wallet and limit of the sla budget, after
+// checking that the wallet has enough unallocated funds for the limit.
+func (c *client) resolveBudget(budget string) (*sla.BudgetAssociation, error) {
+	b, err := sla.ParseBudget(budget)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-
-	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(buff.Bytes()))
+	if b.Wallet == "" {
+		wallets, err := c.budgetClient.ListWallets()
+		if err != nil {
+			return nil, errors.Annotate(err, "failed to list wallets")
+		}
+		for _, w := range wallets.Wallets {
+			if w.Default {
+				b.Wallet = w.Wallet
+				break
+			}
+		}
+		if b.Wallet == "" {
+			return nil, errors.NotFoundf("default wallet")
+		}
+	}
+	wallet, err := c.budgetClient.GetWallet(b.Wallet)
+	if err != nil {
+		return nil, errors.Annotatef(err, "failed to resolve wallet %q", b.Wallet)
+	}
+	limit, err := strconv.ParseFloat(b.Limit, 64)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	response, err := c.client.Do(req)
+	unallocated, err := strconv.ParseFloat(wallet.Total.Unallocated, 64)
+	if err == nil && (math.IsNaN(unallocated) || math.IsInf(unallocated, 0)) {
+		err = errors.NotValidf("amount %q", wallet.Total.Unallocated)
+	}
 	if err != nil {
+		return nil, errors.Annotatef(err, "invalid unallocated amount in wallet %q", b.Wallet)
+	}
+	if limit > unallocated {
+		return nil, errors.Errorf("budget limit %s exceeds the %s unallocated in wallet %q", b.Limit, wallet.Total.Unallocated, b.Wallet)
+	}
+	return &b, nil
+}
+
+// GetSLA returns the sla currently set for the specified model.
+// Invalid model UUIDs are rejected without contacting the service.
+func (c *client) GetSLA(modelUUID string) (*sla.SLA, error) {
+	if err := validateModelUUID(modelUUID); err != nil {
 		return nil, errors.Trace(err)
 	}
+	var respDoc sla.SLA
+	err := c.doRequest("sla.GetSLA", "GET", "/sla/model/"+modelUUID, nil, &respDoc,
+		instrument.ModelKey.String(modelUUID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &respDoc, nil
+}
+
+// ListLevels returns the support levels available to the user.
+func (c *client) ListLevels() ([]sla.SupportLevel, error) {
+	var levels []sla.SupportLevel
+	err := c.doRequest("sla.ListLevels", "GET", "/sla/levels", nil, &levels)
+	if err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+// Unset removes the sla set for the specified model. Invalid model
+// UUIDs are rejected without contacting the service.
+func (c *client) Unset(modelUUID string) error {
+	if err := validateModelUUID(modelUUID); err != nil {
+		return errors.Trace(err)
+	}
+	return c.doRequest("sla.Unset", "DELETE", "/sla/model/"+modelUUID, nil, nil,
+		instrument.ModelKey.String(modelUUID),
+	)
+}
+
+// validateModelUUID checks that modelUUID is a valid UUID, so that it
+// can be used in a request path.
+func validateModelUUID(modelUUID string) error {
+	if !utils.IsValidUUIDString(modelUUID) {
+		return errors.NotValidf("model UUID %q", modelUUID)
+	}
+	return nil
+}
+
+// doRequest sends a request with the given method to the path relative
+// to the api root. If body is not nil it is sent json encoded and if
+// result is not nil the response is decoded into it. The request is
+// traced in a span with the given attributes, and cache hits are
+// reported to the observer separately from the requests sent.
+func (c *client) doRequest(operation, method, path string, body, result interface{}, attrs ...attribute.KeyValue) error {
+	u, err := url.Parse(c.apiRoot + path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var req *http.Request
+	if body != nil {
+		buff := &bytes.Buffer{}
+		encoder := json.NewEncoder(buff)
+		err = encoder.Encode(body)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		req, err = http.NewRequest(method, u.String(), bytes.NewReader(buff.Bytes()))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req, err = http.NewRequest(method, u.String(), nil)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	req, end := instrument.StartSpan(c.tracer, operation, req, attrs...)
+	sent := false
+	response, err := c.cache.Do(operation, req, func(req *http.Request) (*http.Response, error) {
+		sent = true
+		start := time.Now()
+		resp, err := c.limiter.Do(req, func(req *http.Request) (*http.Response, error) {
+			return logging.Do(c.logger, c.logLevel, c.client, operation, req)
+		})
+		instrument.Observe(c.observer, operation, start, resp, err)
+		return resp, err
+	})
+	if req.Method != "GET" {
+		c.cache.Invalidate("sla.")
+	}
+	if sent {
+		end(response, err)
+	} else {
+		instrument.CacheHit(c.observer, operation, req)
+		end(nil, nil)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
 	defer discardClose(response)
 
 	if response.StatusCode != http.StatusOK {
 		respErr := httpErrorResponse{}
 		json.NewDecoder(response.Body).Decode(&respErr)
-		return nil, common.HTTPError{
+		return common.HTTPError{
 			StatusCode: response.StatusCode,
 			Message:    respErr.Error,
 		}
 	}
 
-	var respDoc sla.SLAResponse
-	decoder := json.NewDecoder(response.Body)
-	err = decoder.Decode(&respDoc)
-	if err != nil {
-		return nil, errors.Annotatef(err, "failed to unmarshal the response")
+	if result != nil {
+		decoder := json.NewDecoder(response.Body)
+		err = decoder.Decode(result)
+		if err != nil {
+			return errors.Annotatef(err, "failed to unmarshal the response")
+		}
 	}
-
-	return &respDoc, nil
+	return nil
 }
 
 // discardClose reads any remaining data from the response body and closes it.
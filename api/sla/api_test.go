@@ -8,21 +8,29 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/v3"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/macaroon.v2"
 
+	"github.com/juju/romulus/api/logging"
 	api "github.com/juju/romulus/api/sla"
+	wireformat "github.com/juju/romulus/wireformat/budget"
+	"github.com/juju/romulus/wireformat/common"
 	"github.com/juju/romulus/wireformat/sla"
 )
 
 type clientSuite struct {
 	httpClient *mockHttpClient
 
-	client api.AuthClient
+	client api.Client
 }
 
 var _ = gc.Suite(&clientSuite{})
@@ -62,6 +70,278 @@ func (s *clientSuite) TestAuthorize(c *gc.C) {
 
 }
 
+func (s *clientSuite) TestAuthorizeInvalidLevel(c *gc.C) {
+	_, err := s.client.Authorize(utils.MustNewUUID().String(), "premium", "")
+	c.Assert(err, gc.ErrorMatches, `sla level "premium" not valid`)
+	s.httpClient.CheckNoCalls(c)
+}
+
+func (s *clientSuite) TestAuthorizeInvalidModelUUID(c *gc.C) {
+	_, err := s.client.Authorize("model-uuid", "essential", "")
+	c.Assert(err, gc.ErrorMatches, `model UUID "model-uuid" not valid`)
+	s.httpClient.CheckNoCalls(c)
+}
+
+func (s *clientSuite) TestGetSLA(c *gc.C) {
+	modelUUID := utils.MustNewUUID().String()
+	expiry := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	data, err := json.Marshal(sla.SLA{
+		ModelUUID:         modelUUID,
+		Owner:             "bob",
+		Level:             "standard",
+		Budget:            "personal:model",
+		CredentialsExpiry: expiry,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.httpClient.status = http.StatusOK
+	s.httpClient.body = data
+
+	resp, err := s.client.GetSLA(modelUUID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp, jc.DeepEquals, &sla.SLA{
+		ModelUUID:         modelUUID,
+		Owner:             "bob",
+		Level:             "standard",
+		Budget:            "personal:model",
+		CredentialsExpiry: expiry,
+	})
+	s.httpClient.CheckCall(c, 0, "Do", "GET", "https://api.jujucharms.com/omnibus/v3/sla/model/"+modelUUID)
+}
+
+func (s *clientSuite) TestGetSLANotFound(c *gc.C) {
+	s.httpClient.status = http.StatusNotFound
+	s.httpClient.body = []byte(`{"error":"sla not found"}`)
+
+	_, err := s.client.GetSLA(utils.MustNewUUID().String())
+	c.Assert(err, gc.ErrorMatches, "sla not found")
+	c.Assert(errors.Cause(err), jc.DeepEquals, common.HTTPError{
+		StatusCode: http.StatusNotFound,
+		Message:    "sla not found",
+	})
+}
+
+func (s *clientSuite) TestListLevels(c *gc.C) {
+	levels := []sla.SupportLevel{{
+		Name:        "essential",
+		Description: "Essential support",
+		Price:       "0.50",
+	}, {
+		Name:        "standard",
+		Description: "Standard support",
+		Price:       "1.00",
+	}}
+	data, err := json.Marshal(levels)
+	c.Assert(err, jc.ErrorIsNil)
+	s.httpClient.status = http.StatusOK
+	s.httpClient.body = data
+
+	resp, err := s.client.ListLevels()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp, jc.DeepEquals, levels)
+	s.httpClient.CheckCall(c, 0, "Do", "GET", "https://api.jujucharms.com/omnibus/v3/sla/levels")
+}
+
+func (s *clientSuite) TestUnset(c *gc.C) {
+	s.httpClient.status = http.StatusOK
+
+	modelUUID := utils.MustNewUUID().String()
+	err := s.client.Unset(modelUUID)
+	c.Assert(err, jc.ErrorIsNil)
+	s.httpClient.CheckCall(c, 0, "Do", "DELETE", "https://api.jujucharms.com/omnibus/v3/sla/model/"+modelUUID)
+}
+
+func (s *clientSuite) TestInvalidModelUUID(c *gc.C) {
+	_, err := s.client.GetSLA("../levels")
+	c.Assert(err, gc.ErrorMatches, `model UUID "../levels" not valid`)
+	err = s.client.Unset("../levels")
+	c.Assert(err, gc.ErrorMatches, `model UUID "../levels" not valid`)
+	s.httpClient.CheckNoCalls(c)
+}
+
+func (s *clientSuite) TestAuthorizeResolveBudget(c *gc.C) {
+	data, err := json.Marshal(sla.SLAResponse{Owner: "bob"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.httpClient.status = http.StatusOK
+	s.httpClient.body = data
+	budgetClient := &mockBudgetClient{
+		wallets: wireformat.WalletSummaries{
+			{Wallet: "work"},
+			{Wallet: "personal", Default: true},
+		},
+		unallocated: "20",
+	}
+	client, err := api.NewClient(api.HTTPClient(s.httpClient), api.ResolveBudgets(budgetClient))
+	c.Assert(err, jc.ErrorIsNil)
+
+	modelUUID := utils.MustNewUUID().String()
+	resp, err := client.Authorize(modelUUID, "essential", "10")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Budget, jc.DeepEquals, &sla.BudgetAssociation{Wallet: "personal", Limit: "10"})
+	budgetClient.CheckCalls(c, []testing.StubCall{
+		{FuncName: "ListWallets"},
+		{FuncName: "GetWallet", Args: []interface{}{"personal"}},
+	})
+	s.httpClient.CheckCall(c, 0, "Do", "POST", "https://api.jujucharms.com/omnibus/v3/sla/authorize")
+
+	budgetClient.ResetCalls()
+	resp, err = client.Authorize(modelUUID, "essential", "work:20")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Budget, jc.DeepEquals, &sla.BudgetAssociation{Wallet: "work", Limit: "20"})
+	budgetClient.CheckCalls(c, []testing.StubCall{
+		{FuncName: "GetWallet", Args: []interface{}{"work"}},
+	})
+}
+
+func (s *clientSuite) TestAuthorizeResolveBudgetErrors(c *gc.C) {
+	modelUUID := utils.MustNewUUID().String()
+	tests := []struct {
+		about       string
+		budget      string
+		wallets     wireformat.WalletSummaries
+		unallocated string
+		walletErr   error
+		err         string
+	}{{
+		about:       "insufficient headroom",
+		budget:      "personal:30",
+		unallocated: "20",
+		err:         `budget limit 30 exceeds the 20 unallocated in wallet "personal"`,
+	}, {
+		about:     "unknown wallet",
+		budget:    "missing:10",
+		walletErr: common.HTTPError{StatusCode: http.StatusNotFound, Message: "wallet not found"},
+		err:       `failed to resolve wallet "missing": wallet not found`,
+	}, {
+		about:   "no default wallet",
+		budget:  "10",
+		wallets: wireformat.WalletSummaries{{Wallet: "work"}},
+		err:     "default wallet not found",
+	}, {
+		about:  "invalid limit",
+		budget: "personal:lots",
+		err:    `budget limit "lots" not valid`,
+	}, {
+		about:  "NaN limit",
+		budget: "personal:NaN",
+		err:    `budget limit "NaN" not valid`,
+	}, {
+		about:       "NaN unallocated",
+		budget:      "personal:10",
+		unallocated: "NaN",
+		err:         `invalid unallocated amount in wallet "personal": amount "NaN" not valid`,
+	}, {
+		about:       "infinite unallocated",
+		budget:      "personal:10",
+		unallocated: "Inf",
+		err:         `invalid unallocated amount in wallet "personal": amount "Inf" not valid`,
+	}}
+	for i, test := range tests {
+		c.Logf("test %d: %s", i, test.about)
+		httpClient := &mockHttpClient{status: http.StatusOK}
+		budgetClient := &mockBudgetClient{
+			wallets:     test.wallets,
+			unallocated: test.unallocated,
+		}
+		budgetClient.SetErrors(test.walletErr)
+		client, err := api.NewClient(api.HTTPClient(httpClient), api.ResolveBudgets(budgetClient))
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = client.Authorize(modelUUID, "essential", test.budget)
+		c.Check(err, gc.ErrorMatches, test.err)
+		httpClient.CheckNoCalls(c)
+	}
+}
+
+type mockBudgetClient struct {
+	testing.Stub
+
+	wallets     wireformat.WalletSummaries
+	unallocated string
+}
+
+func (m *mockBudgetClient) ListWallets() (*wireformat.ListWalletsResponse, error) {
+	m.AddCall("ListWallets")
+	return &wireformat.ListWalletsResponse{Wallets: m.wallets}, m.NextErr()
+}
+
+func (m *mockBudgetClient) GetWallet(wallet string) (*wireformat.WalletWithBudgets, error) {
+	m.AddCall("GetWallet", wallet)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return &wireformat.WalletWithBudgets{
+		Total: wireformat.WalletTotals{Unallocated: m.unallocated},
+	}, nil
+}
+
+func (s *clientSuite) TestInstrument(c *gc.C) {
+	observer := &mockObserver{}
+	httpClient := &mockHttpClient{status: http.StatusOK, body: []byte("[]")}
+	client, err := api.NewClient(api.HTTPClient(httpClient), api.Instrument(observer))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.ListLevels()
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient.status = http.StatusNotFound
+	err = client.Unset(utils.MustNewUUID().String())
+	c.Assert(err, gc.NotNil)
+	c.Assert(observer.requests, jc.DeepEquals, []string{
+		"sla.ListLevels 200",
+		"sla.Unset 404",
+	})
+}
+
+func (s *clientSuite) TestTracing(c *gc.C) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	httpClient := &mockHttpClient{status: http.StatusOK, body: []byte("[]")}
+	client, err := api.NewClient(api.HTTPClient(httpClient), api.TracerProvider(provider))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.ListLevels()
+	c.Assert(err, jc.ErrorIsNil)
+	httpClient.status = http.StatusNotFound
+	modelUUID := utils.MustNewUUID().String()
+	err = client.Unset(modelUUID)
+	c.Assert(err, gc.NotNil)
+
+	spans := exporter.GetSpans()
+	c.Assert(spans, gc.HasLen, 2)
+	c.Assert(spans[0].Name, gc.Equals, "sla.ListLevels")
+	c.Assert(spanAttributes(spans[0]), jc.DeepEquals, map[string]string{
+		"romulus.operation": "sla.ListLevels",
+		"http.status_code":  "200",
+	})
+	c.Assert(spans[1].Name, gc.Equals, "sla.Unset")
+	c.Assert(spans[1].Status.Code, gc.Equals, codes.Error)
+	c.Assert(spanAttributes(spans[1]), jc.DeepEquals, map[string]string{
+		"romulus.operation":  "sla.Unset",
+		"romulus.model_uuid": modelUUID,
+		"http.status_code":   "404",
+	})
+}
+
+func (s *clientSuite) TestLogging(c *gc.C) {
+	m, err := macaroon.New(nil, nil, "", macaroon.LatestVersion)
+	c.Assert(err, jc.ErrorIsNil)
+	body, err := json.Marshal(sla.SLAResponse{Owner: "bob", Credentials: m})
+	c.Assert(err, jc.ErrorIsNil)
+	logger := &mockLogger{}
+	httpClient := &mockHttpClient{status: http.StatusOK, body: body}
+	client, err := api.NewClient(api.HTTPClient(httpClient), api.Logging(logger, logging.Bodies))
+	c.Assert(err, jc.ErrorIsNil)
+	modelUUID := utils.MustNewUUID().String()
+	resp, err := client.Authorize(modelUUID, "essential", "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Credentials, gc.NotNil)
+
+	c.Assert(logger.records, gc.HasLen, 1)
+	r := logger.records[0]
+	c.Assert(r.Operation, gc.Equals, "sla.Authorize")
+	c.Assert(r.Method, gc.Equals, "POST")
+	c.Assert(r.URL, gc.Equals, "https://api.jujucharms.com/omnibus/v3/sla/authorize")
+	c.Assert(r.Status, gc.Equals, http.StatusOK)
+	c.Assert(r.RequestBody, gc.Equals, `{"budget":"","model":"`+modelUUID+`","sla":"essential"}`)
+	c.Assert(r.ResponseBody, gc.Equals, `{"credentials":"REDACTED","owner":"bob"}`)
+}
+
 type mockHttpClient struct {
 	testing.Stub
 
@@ -70,7 +350,7 @@ type mockHttpClient struct {
 }
 
 func (m *mockHttpClient) Do(req *http.Request) (*http.Response, error) {
-	m.AddCall("Do", req.URL.String())
+	m.AddCall("Do", req.Method, req.URL.String())
 	return &http.Response{
 		Status:     http.StatusText(m.status),
 		StatusCode: m.status,
@@ -80,3 +360,28 @@ func (m *mockHttpClient) Do(req *http.Request) (*http.Response, error) {
 		Body:       ioutil.NopCloser(bytes.NewReader(m.body)),
 	}, nil
 }
+
+type mockLogger struct {
+	records []logging.Record
+}
+
+func (l *mockLogger) LogRequest(r logging.Record) {
+	l.records = append(l.records, r)
+}
+
+type mockObserver struct {
+	requests []string
+}
+
+func (o *mockObserver) ObserveRequest(operation, status string, duration time.Duration) {
+	o.requests = append(o.requests, operation+" "+status)
+}
+
+// spanAttributes returns the attributes of the span keyed by name.
+func spanAttributes(span tracetest.SpanStub) map[string]string {
+	attrs := make(map[string]string)
+	for _, attr := range span.Attributes {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	return attrs
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"flag"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/romulus/api/budget"
+	wireformat "github.com/juju/romulus/wireformat/budget"
+)
+
+// budgetOptions returns the budget options for the given enforcement
+// policy, if any.
+func budgetOptions(enforcement string) []budget.BudgetOption {
+	if enforcement == "" {
+		return nil
+	}
+	return []budget.BudgetOption{budget.Enforcement(wireformat.EnforcementPolicy(enforcement))}
+}
+
+type createBudgetCommand struct {
+	enforcement string
+}
+
+func (*createBudgetCommand) Info() commandInfo {
+	return commandInfo{
+		Name:    "budget create",
+		Args:    "<wallet> <model-uuid> <limit>",
+		Purpose: "Allocate a budget for a model from a wallet.",
+	}
+}
+
+func (c *createBudgetCommand) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.enforcement, "enforcement", "", "action taken when the limit is reached: notify, block or degrade")
+}
+
+func (c *createBudgetCommand) Run(ctx *cmdContext, args []string) error {
+	if err := checkArgs(args, []string{"<wallet>", "<model-uuid>", "<limit>"}); err != nil {
+		return err
+	}
+	client, err := ctx.budgetClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	response, err := client.CreateBudget(args[0], args[2], args[1], budgetOptions(c.enforcement)...)
+	if err != nil {
+		return errors.Annotate(err, "failed to create the budget")
+	}
+	return ctx.write(response, nil)
+}
+
+type updateBudgetCommand struct {
+	wallet      string
+	enforcement string
+}
+
+func (*updateBudgetCommand) Info() commandInfo {
+	return commandInfo{
+		Name:    "budget update",
+		Args:    "<model-uuid> [<limit>]",
+		Purpose: "Update the limit, wallet or enforcement policy of a model's budget.",
+	}
+}
+
+func (c *updateBudgetCommand) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.wallet, "wallet", "", "move the budget to this wallet")
+	f.StringVar(&c.enforcement, "enforcement", "", "action taken when the limit is reached: notify, block or degrade")
+}
+
+func (c *updateBudgetCommand) Run(ctx *cmdContext, args []string) error {
+	if err := checkArgs(args, []string{"<model-uuid>"}, "<limit>"); err != nil {
+		return err
+	}
+	var limit string
+	if len(args) > 1 {
+		limit = args[1]
+	}
+	if limit == "" && c.wallet == "" && c.enforcement == "" {
+		return errors.New("nothing to update")
+	}
+	client, err := ctx.budgetClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	response, err := client.UpdateBudget(args[0], c.wallet, limit, budgetOptions(c.enforcement)...)
+	if err != nil {
+		return errors.Annotate(err, "failed to update the budget")
+	}
+	return ctx.write(response, nil)
+}
+
+type deleteBudgetCommand struct{}
+
+func (*deleteBudgetCommand) Info() commandInfo {
+	return commandInfo{
+		Name:    "budget delete",
+		Args:    "<model-uuid>",
+		Purpose: "Delete the budget of a model.",
+	}
+}
+
+func (*deleteBudgetCommand) SetFlags(*flag.FlagSet) {}
+
+func (*deleteBudgetCommand) Run(ctx *cmdContext, args []string) error {
+	if err := checkArgs(args, []string{"<model-uuid>"}); err != nil {
+		return err
+	}
+	client, err := ctx.budgetClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	response, err := client.DeleteBudget(args[0])
+	if err != nil {
+		return errors.Annotate(err, "failed to delete the budget")
+	}
+	return ctx.write(response, nil)
+}
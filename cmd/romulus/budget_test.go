@@ -0,0 +1,71 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type budgetSuite struct{}
+
+var _ = gc.Suite(&budgetSuite{})
+
+func (s *budgetSuite) TestCreate(c *gc.C) {
+	server := newFakeServer(c, map[string]response{
+		"POST /wallet/personal/budget": {body: "budget created"},
+	})
+	defer server.Close()
+	stdout, _, code := runCommand(c, "budget", "create", "--api-root", server.URL, "--enforcement", "block", "personal", "model-uuid", "20")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stdout, gc.Equals, "budget created\n")
+	c.Assert(server.Requests(), jc.DeepEquals, []request{{
+		Method: "POST",
+		URL:    "/wallet/personal/budget",
+		Body: map[string]interface{}{
+			"model":       "model-uuid",
+			"limit":       "20",
+			"enforcement": "block",
+		},
+	}})
+}
+
+func (s *budgetSuite) TestCreateInvalidEnforcement(c *gc.C) {
+	_, stderr, code := runCommand(c, "budget", "create", "--enforcement", "shutdown", "personal", "model-uuid", "20")
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(stderr, gc.Equals, "romulus: failed to create the budget: enforcement policy \"shutdown\" not valid\n")
+}
+
+func (s *budgetSuite) TestUpdate(c *gc.C) {
+	server := newFakeServer(c, map[string]response{
+		"PATCH /model/model-uuid/budget": {body: "budget updated"},
+	})
+	defer server.Close()
+	stdout, _, code := runCommand(c, "budget", "update", "--api-root", server.URL, "model-uuid", "30", "--wallet", "work")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stdout, gc.Equals, "budget updated\n")
+	c.Assert(server.Requests(), jc.DeepEquals, []request{{
+		Method: "PATCH",
+		URL:    "/model/model-uuid/budget",
+		Body: map[string]interface{}{
+			"update": map[string]interface{}{"limit": "30", "wallet": "work"},
+		},
+	}})
+}
+
+func (s *budgetSuite) TestUpdateNothing(c *gc.C) {
+	_, stderr, code := runCommand(c, "budget", "update", "model-uuid")
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(stderr, gc.Equals, "romulus: nothing to update\n")
+}
+
+func (s *budgetSuite) TestDelete(c *gc.C) {
+	server := newFakeServer(c, map[string]response{
+		"DELETE /model/model-uuid/budget": {body: "budget deleted"},
+	})
+	defer server.Close()
+	stdout, _, code := runCommand(c, "budget", "delete", "--api-root", server.URL, "model-uuid")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stdout, gc.Equals, "budget deleted\n")
+}
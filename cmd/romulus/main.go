@@ -0,0 +1,215 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The romulus command gives access to the budget, plan and SLA services
+// from the command line.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/romulus/api/auth"
+	"github.com/juju/romulus/api/sla"
+	"github.com/juju/romulus/config"
+)
+
+// command defines a romulus subcommand.
+type command interface {
+	// Info returns the name, arguments and purpose of the command.
+	Info() commandInfo
+	// SetFlags adds the command specific flags to the flag set.
+	SetFlags(f *flag.FlagSet)
+	// Run runs the command with the given positional arguments.
+	Run(ctx *cmdContext, args []string) error
+}
+
+// commandInfo describes a command.
+type commandInfo struct {
+	Name    string
+	Args    string
+	Purpose string
+}
+
+var commands = []command{
+	&listWalletsCommand{},
+	&showWalletCommand{},
+	&createWalletCommand{},
+	&setWalletCommand{},
+	&createBudgetCommand{},
+	&updateBudgetCommand{},
+	&deleteBudgetCommand{},
+	&listPlansCommand{},
+	&authorizePlanCommand{},
+	&setSLACommand{},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command specified by args and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) < 2 || args[0] == "help" {
+		usage(stderr)
+		if len(args) == 1 && args[0] == "help" {
+			return 0
+		}
+		return 2
+	}
+	name := args[0] + " " + args[1]
+	cmd := findCommand(name)
+	if cmd == nil {
+		fmt.Fprintf(stderr, "romulus: unknown command %q\n", name)
+		usage(stderr)
+		return 2
+	}
+	ctx := &cmdContext{
+		stdout: stdout,
+		stderr: stderr,
+	}
+	f := flag.NewFlagSet("romulus "+name, flag.ContinueOnError)
+	f.SetOutput(stderr)
+	f.StringVar(&ctx.configPath, "config", "", "read the client configuration from this `file`")
+	f.StringVar(&ctx.apiRoot, "api-root", "", "the `url` of the romulus API, overriding the configuration")
+	f.StringVar(&ctx.format, "format", "table", "output format: table, json or yaml")
+	cmd.SetFlags(f)
+	f.Usage = func() {
+		info := cmd.Info()
+		fmt.Fprintf(stderr, "usage: romulus %s [flags] %s\n\n%s\n\nflags:\n", info.Name, info.Args, info.Purpose)
+		f.PrintDefaults()
+	}
+	positional, err := parseFlags(f, args[2:])
+	if err == flag.ErrHelp {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+	if err := ctx.checkFormat(); err != nil {
+		fmt.Fprintf(stderr, "romulus: %v\n", err)
+		return 2
+	}
+	if err := cmd.Run(ctx, positional); err != nil {
+		fmt.Fprintf(stderr, "romulus: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parseFlags parses the flags in args, which may be interspersed
+// with positional arguments, and returns the positional arguments.
+func parseFlags(f *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := f.Parse(args); err != nil {
+			return nil, err
+		}
+		args = f.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func findCommand(name string) command {
+	for _, cmd := range commands {
+		if cmd.Info().Name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "usage: romulus <command> [flags] [args]\n\ncommands:\n")
+	infos := make([]commandInfo, len(commands))
+	for i, cmd := range commands {
+		infos[i] = cmd.Info()
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	for _, info := range infos {
+		fmt.Fprintf(w, "    %-18s %s\n", info.Name, info.Purpose)
+	}
+}
+
+// checkArgs checks that args holds exactly the named required
+// arguments, followed by at most len(optional) optional arguments.
+func checkArgs(args []string, required []string, optional ...string) error {
+	if len(args) < len(required) {
+		return errors.Errorf("no %s specified", strings.Trim(required[len(args)], "<>"))
+	}
+	if extra := args[len(required):]; len(extra) > len(optional) {
+		return errors.Errorf("unrecognized args: %q", extra[len(optional):])
+	}
+	return nil
+}
+
+// cmdContext holds the state shared by all commands.
+type cmdContext struct {
+	stdout     io.Writer
+	stderr     io.Writer
+	configPath string
+	apiRoot    string
+	format     string
+}
+
+// config returns the client configuration. Unless another
+// authentication mode is configured, discharges requiring interaction
+// are completed by visiting the URL printed on stderr.
+func (ctx *cmdContext) config() (*config.Config, error) {
+	cfg, err := config.Load(ctx.configPath)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if ctx.apiRoot != "" {
+		cfg.APIRoot = ctx.apiRoot
+	}
+	if cfg.Auth.Mode == "" {
+		cfg.Auth.Mode = config.InteractiveAuth
+	}
+	cfg.Interactor = auth.Interactive(ctx.visit)
+	return cfg, nil
+}
+
+func (ctx *cmdContext) visit(u *url.URL) error {
+	fmt.Fprintf(ctx.stderr, "Please visit %s to log in.\n", u)
+	return nil
+}
+
+func (ctx *cmdContext) budgetClient() (budgetAPI, error) {
+	cfg, err := ctx.config()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cfg.BudgetClient()
+}
+
+func (ctx *cmdContext) planClient() (config.PlanClient, error) {
+	cfg, err := ctx.config()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cfg.PlanClient()
+}
+
+func (ctx *cmdContext) slaClient() (sla.AuthClient, error) {
+	cfg, err := ctx.config()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	budgetClient, err := cfg.BudgetClient()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cfg.SLAClient(sla.ResolveBudgets(budgetClient))
+}
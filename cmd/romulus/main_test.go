@@ -0,0 +1,143 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	stdtesting "testing"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+func Test(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type mainSuite struct{}
+
+var _ = gc.Suite(&mainSuite{})
+
+func (s *mainSuite) TestUsage(c *gc.C) {
+	stdout, stderr, code := runCommand(c, "help")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stdout, gc.Equals, "")
+	c.Assert(stderr, gc.Matches, `(?s)usage: romulus <command> .*budget create .*wallets show .*`)
+}
+
+func (s *mainSuite) TestUnknownCommand(c *gc.C) {
+	_, stderr, code := runCommand(c, "wallets", "frobnicate")
+	c.Assert(code, gc.Equals, 2)
+	c.Assert(stderr, gc.Matches, `(?s)romulus: unknown command "wallets frobnicate"\n.*`)
+}
+
+func (s *mainSuite) TestInvalidFormat(c *gc.C) {
+	_, stderr, code := runCommand(c, "wallets", "list", "--format", "xml")
+	c.Assert(code, gc.Equals, 2)
+	c.Assert(stderr, gc.Equals, "romulus: output format \"xml\" not valid\n")
+}
+
+func (s *mainSuite) TestMissingArgs(c *gc.C) {
+	_, stderr, code := runCommand(c, "wallets", "create", "personal")
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(stderr, gc.Equals, "romulus: no limit specified\n")
+}
+
+func (s *mainSuite) TestExtraArgs(c *gc.C) {
+	_, stderr, code := runCommand(c, "wallets", "show", "personal", "work")
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(stderr, gc.Equals, "romulus: unrecognized args: [\"work\"]\n")
+}
+
+func (s *mainSuite) TestServerError(c *gc.C) {
+	server := newFakeServer(c, map[string]response{
+		"GET /wallet/personal": {code: http.StatusNotFound, body: map[string]string{"error": "wallet not found"}},
+	})
+	defer server.Close()
+	_, stderr, code := runCommand(c, "wallets", "show", "personal", "--api-root", server.URL)
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(stderr, gc.Equals, "romulus: failed to retrieve the wallet: wallet not found\n")
+}
+
+func (s *mainSuite) TestConfigFile(c *gc.C) {
+	server := newFakeServer(c, map[string]response{
+		"DELETE /model/model-uuid/budget": {body: "budget deleted"},
+	})
+	defer server.Close()
+	path := filepath.Join(c.MkDir(), "romulus.yaml")
+	err := ioutil.WriteFile(path, []byte("api-root: "+server.URL+"\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	stdout, _, code := runCommand(c, "budget", "delete", "--config", path, "model-uuid")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stdout, gc.Equals, "budget deleted\n")
+}
+
+// runCommand runs romulus with the given arguments and returns its
+// output and exit code.
+func runCommand(c *gc.C, args ...string) (string, string, int) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
+
+// response is a canned response of the fake server.
+type response struct {
+	code int
+	body interface{}
+}
+
+// request is a request received by the fake server.
+type request struct {
+	Method string
+	URL    string
+	Body   map[string]interface{}
+}
+
+// fakeServer serves canned responses keyed by method and path.
+type fakeServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []request
+}
+
+func newFakeServer(c *gc.C, responses map[string]response) *fakeServer {
+	s := &fakeServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r := request{Method: req.Method, URL: req.URL.RequestURI()}
+		data, err := ioutil.ReadAll(req.Body)
+		c.Check(err, jc.ErrorIsNil)
+		if len(data) > 0 {
+			c.Check(json.Unmarshal(data, &r.Body), jc.ErrorIsNil)
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, r)
+		s.mu.Unlock()
+
+		resp, ok := responses[req.Method+" "+req.URL.Path]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		if resp.code == 0 {
+			resp.code = http.StatusOK
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(resp.code)
+		json.NewEncoder(w).Encode(resp.body)
+	}))
+	return s
+}
+
+func (s *fakeServer) Requests() []request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]request(nil), s.requests...)
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	tableFormat = "table"
+	jsonFormat  = "json"
+	yamlFormat  = "yaml"
+)
+
+func (ctx *cmdContext) checkFormat() error {
+	switch ctx.format {
+	case tableFormat, jsonFormat, yamlFormat:
+		return nil
+	}
+	return errors.NotValidf("output format %q", ctx.format)
+}
+
+// write writes value to stdout in the selected output format. The
+// table function writes the tabular form of the value; if it is nil
+// the value is printed as it is.
+func (ctx *cmdContext) write(value interface{}, table func(w io.Writer)) error {
+	switch ctx.format {
+	case jsonFormat:
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return errors.Trace(err)
+		}
+		_, err = fmt.Fprintf(ctx.stdout, "%s\n", data)
+		return errors.Trace(err)
+	case yamlFormat:
+		// The wire types only have json tags, so the value is
+		// converted through its json form to get the same field
+		// names.
+		data, err := json.Marshal(value)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return errors.Trace(err)
+		}
+		data, err = yaml.Marshal(v)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		_, err = ctx.stdout.Write(data)
+		return errors.Trace(err)
+	}
+	if table == nil {
+		_, err := fmt.Fprintln(ctx.stdout, value)
+		return errors.Trace(err)
+	}
+	tw := tabwriter.NewWriter(ctx.stdout, 0, 1, 2, ' ', 0)
+	table(tw)
+	return errors.Trace(tw.Flush())
+}
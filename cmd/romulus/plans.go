@@ -0,0 +1,82 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/juju/errors"
+)
+
+type listPlansCommand struct{}
+
+func (*listPlansCommand) Info() commandInfo {
+	return commandInfo{
+		Name:    "plans list",
+		Args:    "<charm-url>",
+		Purpose: "List the plans associated with a charm.",
+	}
+}
+
+func (*listPlansCommand) SetFlags(*flag.FlagSet) {}
+
+func (*listPlansCommand) Run(ctx *cmdContext, args []string) error {
+	if err := checkArgs(args, []string{"<charm-url>"}); err != nil {
+		return err
+	}
+	client, err := ctx.planClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	plans, err := client.GetAssociatedPlans(args[0])
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return ctx.write(plans, func(w io.Writer) {
+		fmt.Fprintf(w, "Plan\tCreated on\n")
+		for _, p := range plans {
+			fmt.Fprintf(w, "%s\t%s\n", p.URL, p.CreatedOn)
+		}
+	})
+}
+
+type authorizePlanCommand struct{}
+
+func (*authorizePlanCommand) Info() commandInfo {
+	return commandInfo{
+		Name:    "plans authorize",
+		Args:    "<model-uuid> <charm-url> <application> <plan>",
+		Purpose: "Authorize an application to use a plan and print the resulting macaroon.",
+	}
+}
+
+func (*authorizePlanCommand) SetFlags(*flag.FlagSet) {}
+
+func (*authorizePlanCommand) Run(ctx *cmdContext, args []string) error {
+	if err := checkArgs(args, []string{"<model-uuid>", "<charm-url>", "<application>", "<plan>"}); err != nil {
+		return err
+	}
+	client, err := ctx.planClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	m, err := client.Authorize(args[0], args[1], args[2], args[3], ctx.visit)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if m == nil {
+		return errors.New("no macaroon returned by the plan service")
+	}
+	// A macaroon has no tabular form, so its json encoding is
+	// printed instead.
+	data, err := m.MarshalJSON()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return ctx.write(m, func(w io.Writer) {
+		fmt.Fprintf(w, "%s\n", data)
+	})
+}
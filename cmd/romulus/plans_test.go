@@ -0,0 +1,73 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"encoding/json"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/macaroon.v2"
+
+	"github.com/juju/romulus/wireformat/plan"
+)
+
+type plansSuite struct{}
+
+var _ = gc.Suite(&plansSuite{})
+
+func (s *plansSuite) TestList(c *gc.C) {
+	server := newFakeServer(c, map[string]response{
+		"GET /charm": {body: []plan.Plan{{
+			URL:       "bob/default",
+			CreatedOn: "2026-01-02T00:00:00Z",
+		}, {
+			URL:       "bob/premium",
+			CreatedOn: "2026-02-03T00:00:00Z",
+		}}},
+	})
+	defer server.Close()
+	stdout, stderr, code := runCommand(c, "plans", "list", "--api-root", server.URL, "cs:mysql")
+	c.Assert(stderr, gc.Equals, "")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stdout, gc.Equals, ""+
+		"Plan         Created on\n"+
+		"bob/default  2026-01-02T00:00:00Z\n"+
+		"bob/premium  2026-02-03T00:00:00Z\n")
+	c.Assert(server.Requests()[0].URL, gc.Equals, "/charm?charm-url=cs%3Amysql")
+}
+
+func (s *plansSuite) TestAuthorize(c *gc.C) {
+	m, err := macaroon.New([]byte("key"), []byte("id"), "omnibus", macaroon.LatestVersion)
+	c.Assert(err, jc.ErrorIsNil)
+	server := newFakeServer(c, map[string]response{
+		"POST /plan/authorize": {body: m},
+	})
+	defer server.Close()
+	stdout, _, code := runCommand(c, "plans", "authorize", "--api-root", server.URL,
+		"00000000-0000-0000-0000-000000000000", "cs:mysql", "mysql", "bob/default")
+	c.Assert(code, gc.Equals, 0)
+	var obtained macaroon.Macaroon
+	err = json.Unmarshal([]byte(stdout), &obtained)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(obtained.Signature(), jc.DeepEquals, m.Signature())
+	c.Assert(server.Requests()[0].Body, jc.DeepEquals, map[string]interface{}{
+		"env-uuid":     "00000000-0000-0000-0000-000000000000",
+		"charm-url":    "cs:mysql",
+		"service-name": "mysql",
+		"plan-url":     "bob/default",
+	})
+}
+
+func (s *plansSuite) TestAuthorizeNoMacaroon(c *gc.C) {
+	server := newFakeServer(c, map[string]response{
+		"POST /plan/authorize": {body: nil},
+	})
+	defer server.Close()
+	stdout, stderr, code := runCommand(c, "plans", "authorize", "--api-root", server.URL,
+		"00000000-0000-0000-0000-000000000000", "cs:mysql", "mysql", "bob/default")
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(stdout, gc.Equals, "")
+	c.Assert(stderr, gc.Equals, "romulus: no macaroon returned by the plan service\n")
+}
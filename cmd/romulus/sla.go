@@ -0,0 +1,53 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/juju/errors"
+)
+
+type setSLACommand struct{}
+
+func (*setSLACommand) Info() commandInfo {
+	return commandInfo{
+		Name:    "sla set",
+		Args:    "<model-uuid> <level> [[<wallet>:]<limit>]",
+		Purpose: "Set the support level of a model, funded from the given wallet.",
+	}
+}
+
+func (*setSLACommand) SetFlags(*flag.FlagSet) {}
+
+func (*setSLACommand) Run(ctx *cmdContext, args []string) error {
+	if err := checkArgs(args, []string{"<model-uuid>", "<level>"}, "<budget>"); err != nil {
+		return err
+	}
+	var budget string
+	if len(args) > 2 {
+		budget = args[2]
+	}
+	client, err := ctx.slaClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	response, err := client.Authorize(args[0], args[1], budget)
+	if err != nil {
+		return errors.Annotate(err, "failed to set the sla")
+	}
+	return ctx.write(response, func(w io.Writer) {
+		fmt.Fprintf(w, "Model\tOwner\tSLA\tWallet\tLimit\n")
+		var wallet, limit string
+		if response.Budget != nil {
+			wallet, limit = response.Budget.Wallet, response.Budget.Limit
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", args[0], response.Owner, args[1], wallet, limit)
+		if response.Message != "" {
+			fmt.Fprintf(w, "\n%s\n", response.Message)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	wireformat "github.com/juju/romulus/wireformat/budget"
+	"github.com/juju/romulus/wireformat/sla"
+)
+
+type slaSuite struct{}
+
+var _ = gc.Suite(&slaSuite{})
+
+const modelUUID = "5a5fbc37-5d1e-4d7d-a3f2-87d7d7e3e1a2"
+
+func (s *slaSuite) TestSet(c *gc.C) {
+	server := newFakeServer(c, map[string]response{
+		"GET /wallet/personal": {body: wireformat.WalletWithBudgets{
+			Total: wireformat.WalletTotals{Unallocated: "100"},
+		}},
+		"POST /sla/authorize": {body: sla.SLAResponse{
+			Owner:   "bob",
+			Message: "sla set",
+		}},
+	})
+	defer server.Close()
+	stdout, stderr, code := runCommand(c, "sla", "set", "--api-root", server.URL, modelUUID, "essential", "personal:10")
+	c.Assert(stderr, gc.Equals, "")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stdout, gc.Equals, ""+
+		"Model                                 Owner  SLA        Wallet    Limit\n"+
+		modelUUID+"  bob    essential  personal  10\n"+
+		"\n"+
+		"sla set\n")
+	requests := server.Requests()
+	c.Assert(requests, gc.HasLen, 2)
+	c.Assert(requests[1].Body, jc.DeepEquals, map[string]interface{}{
+		"model":  modelUUID,
+		"sla":    "essential",
+		"budget": "personal:10",
+	})
+}
+
+func (s *slaSuite) TestSetInvalidLevel(c *gc.C) {
+	_, stderr, code := runCommand(c, "sla", "set", modelUUID, "platinum")
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(stderr, gc.Equals, "romulus: failed to set the sla: sla level \"platinum\" not valid\n")
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/romulus/api/budget"
+	wireformat "github.com/juju/romulus/wireformat/budget"
+)
+
+// budgetAPI defines the budget API calls used by the wallet and
+// budget commands.
+type budgetAPI interface {
+	CreateWallet(name, limit string) (string, error)
+	ListWallets() (*wireformat.ListWalletsResponse, error)
+	SetWallet(wallet, limit string) (string, error)
+	GetWallet(wallet string) (*wireformat.WalletWithBudgets, error)
+	CreateBudget(wallet, limit, model string, options ...budget.BudgetOption) (string, error)
+	UpdateBudget(model, wallet, limit string, options ...budget.BudgetOption) (string, error)
+	DeleteBudget(model string) (string, error)
+}
+
+type listWalletsCommand struct{}
+
+func (*listWalletsCommand) Info() commandInfo {
+	return commandInfo{
+		Name:    "wallets list",
+		Purpose: "List the user's wallets.",
+	}
+}
+
+func (*listWalletsCommand) SetFlags(*flag.FlagSet) {}
+
+func (*listWalletsCommand) Run(ctx *cmdContext, args []string) error {
+	if err := checkArgs(args, nil); err != nil {
+		return err
+	}
+	client, err := ctx.budgetClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	wallets, err := client.ListWallets()
+	if err != nil {
+		return errors.Annotate(err, "failed to retrieve wallets")
+	}
+	sort.Sort(wallets.Wallets)
+	return ctx.write(wallets, func(w io.Writer) {
+		fmt.Fprintf(w, "Wallet\tMonthly\tBudgeted\tAvailable\tSpent\n")
+		for _, wallet := range wallets.Wallets {
+			name := wallet.Wallet
+			if wallet.Default {
+				name += "*"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", name, wallet.Limit, wallet.Budgeted, wallet.Available, wallet.Consumed)
+		}
+		total := wallets.Total
+		fmt.Fprintf(w, "Total\t%s\t%s\t%s\t%s\n", total.Limit, total.Budgeted, total.Available, total.Consumed)
+		if wallets.Credit != "" {
+			fmt.Fprintf(w, "\nCredit limit: %s\n", wallets.Credit)
+		}
+	})
+}
+
+type showWalletCommand struct{}
+
+func (*showWalletCommand) Info() commandInfo {
+	return commandInfo{
+		Name:    "wallets show",
+		Args:    "<wallet>",
+		Purpose: "Show a wallet and the budgets allocated from it.",
+	}
+}
+
+func (*showWalletCommand) SetFlags(*flag.FlagSet) {}
+
+func (*showWalletCommand) Run(ctx *cmdContext, args []string) error {
+	if err := checkArgs(args, []string{"<wallet>"}); err != nil {
+		return err
+	}
+	client, err := ctx.budgetClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	wallet, err := client.GetWallet(args[0])
+	if err != nil {
+		return errors.Annotate(err, "failed to retrieve the wallet")
+	}
+	sort.Sort(wireformat.SortedBudgets(wallet.Budgets))
+	return ctx.write(wallet, func(w io.Writer) {
+		fmt.Fprintf(w, "Model\tSpent\tBudgeted\tBy\tUsage\n")
+		for _, b := range wallet.Budgets {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", b.Model, b.Consumed, b.Limit, b.Owner, b.Usage)
+		}
+		total := wallet.Total
+		fmt.Fprintf(w, "Total\t%s\t%s\t\t%s\n", total.Consumed, total.Budgeted, total.Usage)
+		fmt.Fprintf(w, "Wallet\t\t%s\n", wallet.Limit)
+		fmt.Fprintf(w, "Unallocated\t\t%s\n", total.Unallocated)
+	})
+}
+
+type createWalletCommand struct{}
+
+func (*createWalletCommand) Info() commandInfo {
+	return commandInfo{
+		Name:    "wallets create",
+		Args:    "<wallet> <limit>",
+		Purpose: "Create a wallet with the given monthly limit.",
+	}
+}
+
+func (*createWalletCommand) SetFlags(*flag.FlagSet) {}
+
+func (*createWalletCommand) Run(ctx *cmdContext, args []string) error {
+	if err := checkArgs(args, []string{"<wallet>", "<limit>"}); err != nil {
+		return err
+	}
+	client, err := ctx.budgetClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	response, err := client.CreateWallet(args[0], args[1])
+	if err != nil {
+		return errors.Annotate(err, "failed to create the wallet")
+	}
+	return ctx.write(response, nil)
+}
+
+type setWalletCommand struct{}
+
+func (*setWalletCommand) Info() commandInfo {
+	return commandInfo{
+		Name:    "wallets set",
+		Args:    "<wallet> <limit>",
+		Purpose: "Set the monthly limit of a wallet.",
+	}
+}
+
+func (*setWalletCommand) SetFlags(*flag.FlagSet) {}
+
+func (*setWalletCommand) Run(ctx *cmdContext, args []string) error {
+	if err := checkArgs(args, []string{"<wallet>", "<limit>"}); err != nil {
+		return err
+	}
+	client, err := ctx.budgetClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	response, err := client.SetWallet(args[0], args[1])
+	if err != nil {
+		return errors.Annotate(err, "failed to update the wallet")
+	}
+	return ctx.write(response, nil)
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	wireformat "github.com/juju/romulus/wireformat/budget"
+)
+
+type walletsSuite struct{}
+
+var _ = gc.Suite(&walletsSuite{})
+
+var listWalletsResponse = wireformat.ListWalletsResponse{
+	Wallets: wireformat.WalletSummaries{{
+		Owner:     "bob",
+		Wallet:    "work",
+		Limit:     "200",
+		Budgeted:  "100",
+		Available: "150",
+		Consumed:  "50",
+	}, {
+		Owner:     "bob",
+		Wallet:    "personal",
+		Limit:     "50",
+		Budgeted:  "30",
+		Available: "45",
+		Consumed:  "5",
+		Default:   true,
+	}},
+	Total: wireformat.WalletTotals{
+		Limit:     "250",
+		Budgeted:  "130",
+		Available: "195",
+		Consumed:  "55",
+	},
+	Credit: "400",
+}
+
+func (s *walletsSuite) TestList(c *gc.C) {
+	server := newFakeServer(c, map[string]response{
+		"GET /wallet": {body: listWalletsResponse},
+	})
+	defer server.Close()
+	stdout, stderr, code := runCommand(c, "wallets", "list", "--api-root", server.URL)
+	c.Assert(stderr, gc.Equals, "")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stdout, gc.Equals, ""+
+		"Wallet     Monthly  Budgeted  Available  Spent\n"+
+		"personal*  50       30        45         5\n"+
+		"work       200      100       150        50\n"+
+		"Total      250      130       195        55\n"+
+		"\n"+
+		"Credit limit: 400\n")
+}
+
+func (s *walletsSuite) TestListJSON(c *gc.C) {
+	server := newFakeServer(c, map[string]response{
+		"GET /wallet": {body: listWalletsResponse},
+	})
+	defer server.Close()
+	stdout, _, code := runCommand(c, "wallets", "list", "--format=json", "--api-root", server.URL)
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stdout, gc.Matches, `(?s)\{\n  "wallets": \[\n    \{\n      "owner": "bob",\n      "wallet": "personal",.*"credit": "400"\n\}\n`)
+}
+
+func (s *walletsSuite) TestListYAML(c *gc.C) {
+	server := newFakeServer(c, map[string]response{
+		"GET /wallet": {body: listWalletsResponse},
+	})
+	defer server.Close()
+	stdout, _, code := runCommand(c, "wallets", "list", "--format=yaml", "--api-root", server.URL)
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stdout, gc.Matches, `(?s)credit: "400"\ntotal:\n.*wallets:\n- available: "45"\n  budgeted: "30"\n  consumed: "5"\n  default: true\n.*wallet: personal\n.*wallet: work\n`)
+}
+
+func (s *walletsSuite) TestShow(c *gc.C) {
+	server := newFakeServer(c, map[string]response{
+		"GET /wallet/personal": {body: wireformat.WalletWithBudgets{
+			Limit: "4000",
+			Total: wireformat.WalletTotals{
+				Budgeted:    "2200",
+				Unallocated: "1800",
+				Consumed:    "1100",
+				Usage:       "50%",
+			},
+			Budgets: []wireformat.Budget{{
+				Owner:    "user.jess",
+				Limit:    "1000",
+				Consumed: "600",
+				Usage:    "60%",
+				Model:    "model.jess",
+			}, {
+				Owner:    "user.joe",
+				Limit:    "1200",
+				Consumed: "500",
+				Usage:    "42%",
+				Model:    "model.joe",
+			}},
+		}},
+	})
+	defer server.Close()
+	stdout, stderr, code := runCommand(c, "wallets", "show", "personal", "--api-root", server.URL)
+	c.Assert(stderr, gc.Equals, "")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stdout, gc.Equals, ""+
+		"Model        Spent  Budgeted  By         Usage\n"+
+		"model.jess   600    1000      user.jess  60%\n"+
+		"model.joe    500    1200      user.joe   42%\n"+
+		"Total        1100   2200                 50%\n"+
+		"Wallet              4000\n"+
+		"Unallocated         1800\n")
+}
+
+func (s *walletsSuite) TestCreate(c *gc.C) {
+	server := newFakeServer(c, map[string]response{
+		"POST /wallet": {body: "wallet created"},
+	})
+	defer server.Close()
+	stdout, _, code := runCommand(c, "wallets", "create", "--api-root", server.URL, "personal", "100")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stdout, gc.Equals, "wallet created\n")
+	c.Assert(server.Requests(), jc.DeepEquals, []request{{
+		Method: "POST",
+		URL:    "/wallet",
+		Body:   map[string]interface{}{"wallet": "personal", "limit": "100"},
+	}})
+}
+
+func (s *walletsSuite) TestSet(c *gc.C) {
+	server := newFakeServer(c, map[string]response{
+		"PATCH /wallet/personal": {body: "wallet updated"},
+	})
+	defer server.Close()
+	stdout, _, code := runCommand(c, "wallets", "set", "--api-root", server.URL, "personal", "150")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stdout, gc.Equals, "wallet updated\n")
+	c.Assert(server.Requests(), jc.DeepEquals, []request{{
+		Method: "PATCH",
+		URL:    "/wallet/personal",
+		Body: map[string]interface{}{
+			"update": map[string]interface{}{"limit": "150"},
+		},
+	}})
+}
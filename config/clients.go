@@ -0,0 +1,170 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/go-macaroon-bakery/macaroon-bakery/v3/httpbakery"
+	"github.com/juju/errors"
+
+	"github.com/juju/romulus/api/auth"
+	"github.com/juju/romulus/api/budget"
+	"github.com/juju/romulus/api/plan"
+	"github.com/juju/romulus/api/sla"
+)
+
+// PlanClient defines the interface of the plan clients built from
+// the configuration.
+type PlanClient interface {
+	plan.Client
+	plan.AuthorizationClient
+}
+
+// BudgetClient returns a budget client built from the configuration.
+// The given options are applied after those derived from the
+// configuration, so they can override them.
+func (c *Config) BudgetClient(options ...budget.ClientOption) (budget.Client, error) {
+	a, h, err := c.authentication()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	opts := []budget.ClientOption{budget.APIRoot(c.APIRoot), budget.Authentication(a)}
+	if h != nil {
+		opts = append(opts, budget.HTTPClient(h))
+	}
+	return budget.NewClient(append(opts, options...)...)
+}
+
+// PlanClient returns a plan client built from the configuration.
+// The given options are applied after those derived from the
+// configuration, so they can override them.
+func (c *Config) PlanClient(options ...plan.ClientOption) (PlanClient, error) {
+	a, h, err := c.authentication()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	opts := []plan.ClientOption{plan.APIRoot(c.APIRoot), plan.Authentication(a)}
+	if h != nil {
+		opts = append(opts, plan.HTTPClient(h))
+	}
+	return plan.NewClient(append(opts, options...)...)
+}
+
+// SLAClient returns an sla client built from the configuration.
+// The given options are applied after those derived from the
+// configuration, so they can override them.
+func (c *Config) SLAClient(options ...sla.ClientOption) (sla.Client, error) {
+	a, h, err := c.authentication()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	opts := []sla.ClientOption{sla.APIRoot(c.APIRoot), sla.Authentication(a)}
+	if h != nil {
+		opts = append(opts, sla.HTTPClient(h))
+	}
+	return sla.NewClient(append(opts, options...)...)
+}
+
+// HTTPClient returns the http client used to send API requests, with
+// the configured timeout, retry policy and proxy.
+func (c *Config) HTTPClient() (*http.Client, error) {
+	var tlsConfig *tls.Config
+	if c.Auth.Mode == CertificateAuth {
+		var err error
+		tlsConfig, err = c.tlsConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return c.httpClient(tlsConfig)
+}
+
+func (c *Config) httpClient(tlsConfig *tls.Config) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c.Proxy != "" {
+		proxy, err := url.Parse(c.Proxy)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	var rt http.RoundTripper = transport
+	if c.Retry.Attempts > 1 {
+		rt = &retryTransport{policy: c.Retry, next: transport}
+	}
+	return &http.Client{
+		Transport: rt,
+		Timeout:   c.Timeout,
+	}, nil
+}
+
+// authentication returns the authenticator for the configured mode
+// and, if the authenticator does not send requests itself, the http
+// client it should use.
+func (c *Config) authentication() (auth.Authenticator, auth.HTTPClient, error) {
+	switch c.Auth.Mode {
+	case TokenAuth:
+		h, err := c.httpClient(nil)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		return auth.BearerToken(c.Auth.Token), h, nil
+	case CertificateAuth:
+		tlsConfig, err := c.tlsConfig()
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		h, err := c.httpClient(tlsConfig)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		return auth.ClientCertificate(tlsConfig.Certificates[0], tlsConfig.RootCAs), h, nil
+	}
+	h, err := c.httpClient(nil)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	bakeryClient := httpbakery.NewClient()
+	h.Jar = bakeryClient.Client.Jar
+	bakeryClient.Client = h
+	if c.Auth.Mode == InteractiveAuth {
+		interactor := c.Interactor
+		if interactor == nil {
+			interactor = auth.Interactive(httpbakery.OpenWebBrowser)
+		}
+		bakeryClient.AddInteractor(interactor)
+	} else {
+		bakeryClient.AddInteractor(auth.NonInteractive())
+	}
+	return auth.Bakery(bakeryClient), nil, nil
+}
+
+// tlsConfig returns the TLS configuration holding the client certificate.
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(c.Auth.CertFile, c.Auth.KeyFile)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot load client certificate")
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if c.Auth.CAFile != "" {
+		data, err := ioutil.ReadFile(c.Auth.CAFile)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot read CA certificates")
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(data) {
+			return nil, errors.Errorf("no CA certificates found in %q", c.Auth.CAFile)
+		}
+		tlsConfig.RootCAs = roots
+	}
+	return tlsConfig, nil
+}
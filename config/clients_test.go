@@ -0,0 +1,126 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/romulus/config"
+	wireformat "github.com/juju/romulus/wireformat/budget"
+)
+
+type clientsSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&clientsSuite{})
+
+func (s *clientsSuite) TestBudgetClientToken(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		c.Check(req.URL.Path, gc.Equals, "/omnibus/v3/wallet")
+		c.Check(req.Header.Get("Authorization"), gc.Equals, "Bearer secret")
+		json.NewEncoder(w).Encode(wireformat.ListWalletsResponse{
+			Wallets: wireformat.WalletSummaries{{Wallet: "personal"}},
+		})
+	}))
+	defer server.Close()
+	cfg := &config.Config{
+		APIRoot: server.URL + "/omnibus/v3",
+		Auth: config.AuthConfig{
+			Mode:  config.TokenAuth,
+			Token: "secret",
+		},
+	}
+	client, err := cfg.BudgetClient()
+	c.Assert(err, jc.ErrorIsNil)
+	wallets, err := client.ListWallets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(wallets.Wallets, gc.HasLen, 1)
+}
+
+func (s *clientsSuite) TestRetry(c *gc.C) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(wireformat.WalletWithBudgets{Limit: "100"})
+	}))
+	defer server.Close()
+	cfg := &config.Config{
+		APIRoot: server.URL,
+		Retry: config.RetryConfig{
+			Attempts: 3,
+			Delay:    time.Millisecond,
+		},
+	}
+	client, err := cfg.BudgetClient()
+	c.Assert(err, jc.ErrorIsNil)
+	wallet, err := client.GetWallet("personal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(wallet.Limit, gc.Equals, "100")
+	c.Assert(atomic.LoadInt32(&calls), gc.Equals, int32(3))
+}
+
+func (s *clientsSuite) TestNoRetryForPost(c *gc.C) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "unavailable"})
+	}))
+	defer server.Close()
+	cfg := &config.Config{
+		APIRoot: server.URL,
+		Retry: config.RetryConfig{
+			Attempts: 3,
+			Delay:    time.Millisecond,
+		},
+	}
+	client, err := cfg.BudgetClient()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.CreateWallet("personal", "100")
+	c.Assert(err, gc.ErrorMatches, "service unavailable")
+	c.Assert(atomic.LoadInt32(&calls), gc.Equals, int32(1))
+}
+
+func (s *clientsSuite) TestProxy(c *gc.C) {
+	proxied := make(chan string, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		proxied <- req.URL.String()
+		json.NewEncoder(w).Encode([]interface{}{})
+	}))
+	defer proxy.Close()
+	cfg := &config.Config{
+		APIRoot: "http://omnibus.invalid/v3",
+		Proxy:   proxy.URL,
+	}
+	client, err := cfg.PlanClient()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.GetAssociatedPlans("cs:mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(<-proxied, gc.Equals, "http://omnibus.invalid/v3/charm?charm-url=cs%3Amysql")
+}
+
+func (s *clientsSuite) TestSLAClient(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		c.Check(req.URL.Path, gc.Equals, "/sla/levels")
+		json.NewEncoder(w).Encode([]interface{}{})
+	}))
+	defer server.Close()
+	cfg := &config.Config{APIRoot: server.URL}
+	client, err := cfg.SLAClient()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.ListLevels()
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package config loads the configuration shared by the romulus API
+// clients and builds clients from it.
+package config
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-macaroon-bakery/macaroon-bakery/v3/httpbakery"
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/romulus"
+)
+
+// AuthMode is the method used to authenticate API requests.
+type AuthMode string
+
+const (
+	// BakeryAuth acquires macaroon discharges without user
+	// interaction. Discharges that require interaction fail with
+	// an auth.InteractionRequiredError.
+	BakeryAuth AuthMode = "bakery"
+	// InteractiveAuth acquires macaroon discharges, completing any
+	// interaction with the configured Interactor.
+	InteractiveAuth AuthMode = "interactive"
+	// TokenAuth sends a bearer token with each request.
+	TokenAuth AuthMode = "token"
+	// CertificateAuth authenticates with a TLS client certificate.
+	CertificateAuth AuthMode = "certificate"
+)
+
+// Config holds the configuration of the romulus API clients.
+type Config struct {
+	// APIRoot holds the base URL of the API. It defaults to
+	// romulus.DefaultAPIRoot.
+	APIRoot string `yaml:"api-root,omitempty"`
+	// Timeout holds the time limit for a single request, including
+	// any retries. Zero means no limit.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// Retry holds the policy for retrying failed requests.
+	Retry RetryConfig `yaml:"retry,omitempty"`
+	// Auth holds the authentication settings.
+	Auth AuthConfig `yaml:"auth,omitempty"`
+	// Proxy holds the URL of the proxy used for API requests. If
+	// empty, the proxy is taken from the standard HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY environment variables.
+	Proxy string `yaml:"proxy,omitempty"`
+
+	// Interactor is used to complete discharges with the
+	// InteractiveAuth mode. It defaults to opening a web browser.
+	Interactor httpbakery.Interactor `yaml:"-"`
+}
+
+// RetryConfig defines the policy for retrying idempotent requests that
+// fail with a network error or a temporary server error.
+type RetryConfig struct {
+	// Attempts holds the maximum number of attempts made for each
+	// request. Values below 2 disable retries.
+	Attempts int `yaml:"attempts,omitempty"`
+	// Delay holds the time to wait before the first retry. The
+	// delay doubles with each further retry.
+	Delay time.Duration `yaml:"delay,omitempty"`
+	// MaxDelay, if set, limits the time to wait between retries.
+	MaxDelay time.Duration `yaml:"max-delay,omitempty"`
+}
+
+// AuthConfig holds the authentication settings.
+type AuthConfig struct {
+	// Mode holds the authentication method. An empty mode is the
+	// same as BakeryAuth.
+	Mode AuthMode `yaml:"mode,omitempty"`
+	// Token holds the bearer token used with TokenAuth.
+	Token string `yaml:"token,omitempty"`
+	// CertFile and KeyFile hold the paths of the PEM encoded client
+	// certificate and key used with CertificateAuth.
+	CertFile string `yaml:"cert-file,omitempty"`
+	KeyFile  string `yaml:"key-file,omitempty"`
+	// CAFile, if set, holds the path of the PEM encoded CA
+	// certificates used to verify the service with
+	// CertificateAuth.
+	CAFile string `yaml:"ca-file,omitempty"`
+}
+
+// Environment variables that override the values read from the
+// configuration file.
+const (
+	EnvAPIRoot       = "ROMULUS_API_ROOT"
+	EnvTimeout       = "ROMULUS_TIMEOUT"
+	EnvRetryAttempts = "ROMULUS_RETRY_ATTEMPTS"
+	EnvRetryDelay    = "ROMULUS_RETRY_DELAY"
+	EnvRetryMaxDelay = "ROMULUS_RETRY_MAX_DELAY"
+	EnvAuthMode      = "ROMULUS_AUTH_MODE"
+	EnvAuthToken     = "ROMULUS_AUTH_TOKEN"
+	EnvAuthCertFile  = "ROMULUS_AUTH_CERT_FILE"
+	EnvAuthKeyFile   = "ROMULUS_AUTH_KEY_FILE"
+	EnvAuthCAFile    = "ROMULUS_AUTH_CA_FILE"
+	EnvProxy         = "ROMULUS_PROXY"
+)
+
+// Load reads the configuration from the YAML file at path, if path is
+// not empty, and then applies any overrides from the environment.
+func Load(path string) (*Config, error) {
+	config := &Config{}
+	if path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot read configuration")
+		}
+		if err := yaml.UnmarshalStrict(data, config); err != nil {
+			return nil, errors.Annotatef(err, "cannot parse configuration %q", path)
+		}
+	}
+	if err := config.applyEnvironment(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if config.APIRoot == "" {
+		config.APIRoot = romulus.DefaultAPIRoot
+	}
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return config, nil
+}
+
+func (c *Config) applyEnvironment() error {
+	setString := func(name string, v *string) {
+		if value := os.Getenv(name); value != "" {
+			*v = value
+		}
+	}
+	setDuration := func(name string, v *time.Duration) error {
+		value := os.Getenv(name)
+		if value == "" {
+			return nil
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.Annotatef(err, "invalid %s", name)
+		}
+		*v = d
+		return nil
+	}
+	setString(EnvAPIRoot, &c.APIRoot)
+	if err := setDuration(EnvTimeout, &c.Timeout); err != nil {
+		return err
+	}
+	if value := os.Getenv(EnvRetryAttempts); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.Annotatef(err, "invalid %s", EnvRetryAttempts)
+		}
+		c.Retry.Attempts = n
+	}
+	if err := setDuration(EnvRetryDelay, &c.Retry.Delay); err != nil {
+		return err
+	}
+	if err := setDuration(EnvRetryMaxDelay, &c.Retry.MaxDelay); err != nil {
+		return err
+	}
+	if value := os.Getenv(EnvAuthMode); value != "" {
+		c.Auth.Mode = AuthMode(value)
+	}
+	setString(EnvAuthToken, &c.Auth.Token)
+	setString(EnvAuthCertFile, &c.Auth.CertFile)
+	setString(EnvAuthKeyFile, &c.Auth.KeyFile)
+	setString(EnvAuthCAFile, &c.Auth.CAFile)
+	setString(EnvProxy, &c.Proxy)
+	return nil
+}
+
+// Validate checks the configuration for errors.
+func (c *Config) Validate() error {
+	if _, err := url.Parse(c.APIRoot); err != nil {
+		return errors.NotValidf("api root %q", c.APIRoot)
+	}
+	if c.Proxy != "" {
+		if _, err := url.Parse(c.Proxy); err != nil {
+			return errors.NotValidf("proxy %q", c.Proxy)
+		}
+	}
+	if c.Timeout < 0 {
+		return errors.NotValidf("negative timeout")
+	}
+	if c.Retry.Attempts < 0 || c.Retry.Delay < 0 || c.Retry.MaxDelay < 0 {
+		return errors.NotValidf("negative retry setting")
+	}
+	switch c.Auth.Mode {
+	case "", BakeryAuth, InteractiveAuth:
+	case TokenAuth:
+		if c.Auth.Token == "" {
+			return errors.New("token authentication requires a token")
+		}
+	case CertificateAuth:
+		if c.Auth.CertFile == "" || c.Auth.KeyFile == "" {
+			return errors.New("certificate authentication requires a certificate and key file")
+		}
+	default:
+		return errors.NotValidf("auth mode %q", c.Auth.Mode)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/romulus"
+	"github.com/juju/romulus/config"
+)
+
+type configSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&configSuite{})
+
+func (s *configSuite) writeConfig(c *gc.C, content string) string {
+	path := filepath.Join(c.MkDir(), "romulus.yaml")
+	err := ioutil.WriteFile(path, []byte(content), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *configSuite) TestDefaults(c *gc.C) {
+	cfg, err := config.Load("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg, jc.DeepEquals, &config.Config{
+		APIRoot: romulus.DefaultAPIRoot,
+	})
+}
+
+func (s *configSuite) TestLoadFile(c *gc.C) {
+	path := s.writeConfig(c, `
+api-root: https://example.com/omnibus/v3
+timeout: 30s
+retry:
+  attempts: 3
+  delay: 100ms
+  max-delay: 1s
+auth:
+  mode: token
+  token: secret
+proxy: http://proxy.example.com:3128
+`)
+	cfg, err := config.Load(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg, jc.DeepEquals, &config.Config{
+		APIRoot: "https://example.com/omnibus/v3",
+		Timeout: 30 * time.Second,
+		Retry: config.RetryConfig{
+			Attempts: 3,
+			Delay:    100 * time.Millisecond,
+			MaxDelay: time.Second,
+		},
+		Auth: config.AuthConfig{
+			Mode:  config.TokenAuth,
+			Token: "secret",
+		},
+		Proxy: "http://proxy.example.com:3128",
+	})
+}
+
+func (s *configSuite) TestEnvironmentOverrides(c *gc.C) {
+	path := s.writeConfig(c, `
+api-root: https://example.com/omnibus/v3
+timeout: 30s
+`)
+	s.PatchEnvironment(config.EnvAPIRoot, "https://staging.example.com/omnibus/v3")
+	s.PatchEnvironment(config.EnvTimeout, "5s")
+	s.PatchEnvironment(config.EnvRetryAttempts, "2")
+	s.PatchEnvironment(config.EnvAuthMode, "interactive")
+	cfg, err := config.Load(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.APIRoot, gc.Equals, "https://staging.example.com/omnibus/v3")
+	c.Assert(cfg.Timeout, gc.Equals, 5*time.Second)
+	c.Assert(cfg.Retry.Attempts, gc.Equals, 2)
+	c.Assert(cfg.Auth.Mode, gc.Equals, config.InteractiveAuth)
+}
+
+func (s *configSuite) TestInvalidEnvironment(c *gc.C) {
+	s.PatchEnvironment(config.EnvTimeout, "soon")
+	_, err := config.Load("")
+	c.Assert(err, gc.ErrorMatches, `invalid ROMULUS_TIMEOUT: time: invalid duration "soon"`)
+}
+
+func (s *configSuite) TestMissingFile(c *gc.C) {
+	_, err := config.Load(filepath.Join(c.MkDir(), "missing.yaml"))
+	c.Assert(err, gc.ErrorMatches, "cannot read configuration: .*")
+}
+
+func (s *configSuite) TestUnknownField(c *gc.C) {
+	path := s.writeConfig(c, "api-rot: https://example.com\n")
+	_, err := config.Load(path)
+	c.Assert(err, gc.ErrorMatches, `(?s)cannot parse configuration ".*": .*field api-rot not found.*`)
+}
+
+func (s *configSuite) TestValidate(c *gc.C) {
+	tests := []struct {
+		config config.Config
+		err    string
+	}{{
+		config: config.Config{Auth: config.AuthConfig{Mode: "password"}},
+		err:    `auth mode "password" not valid`,
+	}, {
+		config: config.Config{Auth: config.AuthConfig{Mode: config.TokenAuth}},
+		err:    "token authentication requires a token",
+	}, {
+		config: config.Config{Auth: config.AuthConfig{Mode: config.CertificateAuth, CertFile: "cert.pem"}},
+		err:    "certificate authentication requires a certificate and key file",
+	}, {
+		config: config.Config{Timeout: -time.Second},
+		err:    "negative timeout not valid",
+	}, {
+		config: config.Config{Retry: config.RetryConfig{Attempts: -1}},
+		err:    "negative retry setting not valid",
+	}}
+	for i, test := range tests {
+		c.Logf("test %d", i)
+		c.Check(test.config.Validate(), gc.ErrorMatches, test.err)
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/romulus"
+)
+
+// ProductionProfile is the name of the profile that every registry
+// starts with, which uses romulus.DefaultAPIRoot.
+const ProductionProfile = "production"
+
+// EnvProfile holds the name of the environment variable that selects
+// the current profile when a registry is loaded.
+const EnvProfile = "ROMULUS_PROFILE"
+
+// Registry holds named configuration profiles, such as production,
+// staging and a local fake, each with its own API root and
+// authentication settings, so that clients for several services can
+// be used in the same process. It is safe for concurrent use.
+//
+// Clients are built from a profile's configuration:
+//
+//	staging, err := registry.Profile("staging")
+//	...
+//	client, err := staging.BudgetClient()
+type Registry struct {
+	mu       sync.RWMutex
+	profiles map[string]Config
+	current  string
+}
+
+// NewRegistry returns a registry holding only the production profile,
+// which is selected.
+func NewRegistry() *Registry {
+	return &Registry{
+		profiles: map[string]Config{
+			ProductionProfile: {APIRoot: romulus.DefaultAPIRoot},
+		},
+		current: ProductionProfile,
+	}
+}
+
+// registryFile is the format of a profiles file.
+type registryFile struct {
+	Current  string            `yaml:"current,omitempty"`
+	Profiles map[string]Config `yaml:"profiles"`
+}
+
+// LoadRegistry reads the profiles from the YAML file at path, which
+// has the form:
+//
+//	current: staging
+//	profiles:
+//	  staging:
+//	    api-root: https://staging.example.com/omnibus/v3
+//	  local:
+//	    api-root: http://localhost:8080
+//	    auth:
+//	      mode: token
+//	      token: secret
+//
+// The profiles are added to those of NewRegistry, and profiles without
+// an API root use romulus.DefaultAPIRoot. The current profile is taken
+// from the environment, then the file, and defaults to production.
+func LoadRegistry(path string) (*Registry, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read profiles")
+	}
+	var f registryFile
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, errors.Annotatef(err, "cannot parse profiles %q", path)
+	}
+	r := NewRegistry()
+	for name, config := range f.Profiles {
+		config := config
+		if config.APIRoot == "" {
+			config.APIRoot = romulus.DefaultAPIRoot
+		}
+		if err := r.Set(name, &config); err != nil {
+			return nil, errors.Annotatef(err, "invalid profile %q", name)
+		}
+	}
+	current := f.Current
+	if env := os.Getenv(EnvProfile); env != "" {
+		current = env
+	}
+	if current != "" {
+		if err := r.Select(current); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return r, nil
+}
+
+// Set adds or replaces the named profile.
+func (r *Registry) Set(name string, config *Config) error {
+	if name == "" {
+		return errors.NotValidf("empty profile name")
+	}
+	if err := config.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.profiles[name] = *config
+	return nil
+}
+
+// Remove removes the named profile. The current profile cannot be
+// removed.
+func (r *Registry) Remove(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.profiles[name]; !ok {
+		return errors.NotFoundf("profile %q", name)
+	}
+	if name == r.current {
+		return errors.Errorf("cannot remove current profile %q", name)
+	}
+	delete(r.profiles, name)
+	return nil
+}
+
+// Names returns the names of the profiles in alphabetical order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.profiles))
+	for name := range r.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Profile returns a copy of the named profile's configuration.
+func (r *Registry) Profile(name string) (*Config, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	config, ok := r.profiles[name]
+	if !ok {
+		return nil, errors.NotFoundf("profile %q", name)
+	}
+	return &config, nil
+}
+
+// Select makes the named profile the current profile.
+func (r *Registry) Select(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.profiles[name]; !ok {
+		return errors.NotFoundf("profile %q", name)
+	}
+	r.current = name
+	return nil
+}
+
+// Current returns the name of the current profile and a copy of its
+// configuration.
+func (r *Registry) Current() (string, *Config) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	config := r.profiles[r.current]
+	return r.current, &config
+}
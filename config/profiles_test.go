@@ -0,0 +1,142 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/romulus"
+	"github.com/juju/romulus/config"
+	wireformat "github.com/juju/romulus/wireformat/budget"
+)
+
+type profilesSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&profilesSuite{})
+
+const profilesFile = `
+current: staging
+profiles:
+  staging:
+    api-root: https://staging.example.com/omnibus/v3
+  local:
+    api-root: http://localhost:8080
+    auth:
+      mode: token
+      token: secret
+`
+
+func (s *profilesSuite) writeProfiles(c *gc.C, content string) string {
+	path := filepath.Join(c.MkDir(), "profiles.yaml")
+	err := ioutil.WriteFile(path, []byte(content), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *profilesSuite) TestNewRegistry(c *gc.C) {
+	r := config.NewRegistry()
+	c.Assert(r.Names(), jc.DeepEquals, []string{config.ProductionProfile})
+	name, cfg := r.Current()
+	c.Assert(name, gc.Equals, config.ProductionProfile)
+	c.Assert(cfg.APIRoot, gc.Equals, romulus.DefaultAPIRoot)
+}
+
+func (s *profilesSuite) TestLoadRegistry(c *gc.C) {
+	r, err := config.LoadRegistry(s.writeProfiles(c, profilesFile))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Names(), jc.DeepEquals, []string{"local", "production", "staging"})
+	name, cfg := r.Current()
+	c.Assert(name, gc.Equals, "staging")
+	c.Assert(cfg.APIRoot, gc.Equals, "https://staging.example.com/omnibus/v3")
+	local, err := r.Profile("local")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(local, jc.DeepEquals, &config.Config{
+		APIRoot: "http://localhost:8080",
+		Auth: config.AuthConfig{
+			Mode:  config.TokenAuth,
+			Token: "secret",
+		},
+	})
+}
+
+func (s *profilesSuite) TestLoadRegistryEnvironment(c *gc.C) {
+	s.PatchEnvironment(config.EnvProfile, "local")
+	r, err := config.LoadRegistry(s.writeProfiles(c, profilesFile))
+	c.Assert(err, jc.ErrorIsNil)
+	name, _ := r.Current()
+	c.Assert(name, gc.Equals, "local")
+
+	s.PatchEnvironment(config.EnvProfile, "qa")
+	_, err = config.LoadRegistry(s.writeProfiles(c, profilesFile))
+	c.Assert(err, gc.ErrorMatches, `profile "qa" not found`)
+}
+
+func (s *profilesSuite) TestLoadRegistryInvalidProfile(c *gc.C) {
+	_, err := config.LoadRegistry(s.writeProfiles(c, `
+profiles:
+  local:
+    auth:
+      mode: token
+`))
+	c.Assert(err, gc.ErrorMatches, `invalid profile "local": token authentication requires a token`)
+}
+
+func (s *profilesSuite) TestSelectAndRemove(c *gc.C) {
+	r := config.NewRegistry()
+	err := r.Set("local", &config.Config{APIRoot: "http://localhost:8080"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Select("staging"), gc.ErrorMatches, `profile "staging" not found`)
+	c.Assert(r.Select("local"), jc.ErrorIsNil)
+	c.Assert(r.Remove("local"), gc.ErrorMatches, `cannot remove current profile "local"`)
+	c.Assert(r.Remove(config.ProductionProfile), jc.ErrorIsNil)
+	c.Assert(r.Names(), jc.DeepEquals, []string{"local"})
+	_, err = r.Profile(config.ProductionProfile)
+	c.Assert(err, gc.ErrorMatches, `profile "production" not found`)
+}
+
+func (s *profilesSuite) TestProfileIsCopy(c *gc.C) {
+	r := config.NewRegistry()
+	cfg, err := r.Profile(config.ProductionProfile)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg.APIRoot = "http://localhost:8080"
+	_, current := r.Current()
+	c.Assert(current.APIRoot, gc.Equals, romulus.DefaultAPIRoot)
+}
+
+func (s *profilesSuite) TestClientsPerProfile(c *gc.C) {
+	newServer := func(wallet string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			json.NewEncoder(w).Encode(wireformat.ListWalletsResponse{
+				Wallets: wireformat.WalletSummaries{{Wallet: wallet}},
+			})
+		}))
+	}
+	staging := newServer("staging-wallet")
+	defer staging.Close()
+	local := newServer("local-wallet")
+	defer local.Close()
+
+	r := config.NewRegistry()
+	c.Assert(r.Set("staging", &config.Config{APIRoot: staging.URL}), jc.ErrorIsNil)
+	c.Assert(r.Set("local", &config.Config{APIRoot: local.URL}), jc.ErrorIsNil)
+	for _, name := range []string{"staging", "local"} {
+		cfg, err := r.Profile(name)
+		c.Assert(err, jc.ErrorIsNil)
+		client, err := cfg.BudgetClient()
+		c.Assert(err, jc.ErrorIsNil)
+		wallets, err := client.ListWallets()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(wallets.Wallets[0].Wallet, gc.Equals, name+"-wallet")
+	}
+}
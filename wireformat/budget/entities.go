@@ -7,13 +7,19 @@ package budget
 
 import (
 	"strings"
+	"time"
+
+	"github.com/juju/errors"
 )
 
 // WalletWithBudgets represents the current state of the wallet and its budgets.
 type WalletWithBudgets struct {
-	Limit   string       `json:"limit, omitempty"`
+	Limit   string       `json:"limit,omitempty"`
 	Total   WalletTotals `json:"total"`
-	Budgets []Budget     `json:"budgets, omitempty"`
+	Budgets []Budget     `json:"budgets,omitempty"`
+	// Next holds the cursor used to request the next page of
+	// budgets. It is empty on the last page.
+	Next string `json:"next,omitempty"`
 }
 
 // SortedBudgets have additional methods that allow for sorting budgets.
@@ -34,8 +40,10 @@ func (a SortedBudgets) Less(i, j int) bool {
 	return a[i].SortableKey() < a[j].SortableKey()
 }
 
+// WalletTotals holds the aggregate figures of a wallet or of all of
+// the user's wallets.
 type WalletTotals struct {
-	Limit       string `json:"limit, omitempty"`
+	Limit       string `json:"limit,omitempty"`
 	Budgeted    string `json:"budgeted"`
 	Available   string `json:"available"`
 	Unallocated string `json:"unallocated"`
@@ -50,6 +58,36 @@ type Budget struct {
 	Consumed string `json:"consumed"`
 	Usage    string `json:"usage"`
 	Model    string `json:"model"`
+	Wallet   string `json:"wallet,omitempty"`
+	// AlertThresholds holds the usage percentages at which
+	// alerts are raised for the budget.
+	AlertThresholds []int `json:"alert-thresholds,omitempty"`
+	// Enforcement holds the action taken when consumption reaches
+	// the limit. An empty policy is treated as NotifyPolicy.
+	Enforcement EnforcementPolicy `json:"enforcement,omitempty"`
+}
+
+// EnforcementPolicy defines what happens when a budget's consumption
+// reaches its limit.
+type EnforcementPolicy string
+
+const (
+	// NotifyPolicy only notifies the budget owner.
+	NotifyPolicy EnforcementPolicy = "notify"
+	// BlockPolicy prevents new units from being added to the model.
+	BlockPolicy EnforcementPolicy = "block"
+	// DegradePolicy sets the meter status of the model's units
+	// to degraded.
+	DegradePolicy EnforcementPolicy = "degrade"
+)
+
+// Validate checks that the policy is a known enforcement policy.
+func (p EnforcementPolicy) Validate() error {
+	switch p {
+	case NotifyPolicy, BlockPolicy, DegradePolicy:
+		return nil
+	}
+	return errors.NotValidf("enforcement policy %q", string(p))
 }
 
 // SortableKey returns a key by which allocations can be sorted.
@@ -57,11 +95,19 @@ func (a Budget) SortableKey() string {
 	return a.Model
 }
 
+// ListBudgetsResponse is returned by the ListBudgets API call.
+type ListBudgetsResponse struct {
+	Budgets SortedBudgets `json:"budgets"`
+}
+
 // ListWalletsResponse is returned by the ListBdugets API call.
 type ListWalletsResponse struct {
-	Wallets WalletSummaries `json:"wallets, omitempty"`
-	Total   WalletTotals    `json:"total, omitempty"`
-	Credit  string          `json:"credit, omitempty"`
+	Wallets WalletSummaries `json:"wallets,omitempty"`
+	Total   WalletTotals    `json:"total"`
+	Credit  string          `json:"credit,omitempty"`
+	// Next holds the cursor used to request the next page of
+	// wallets. It is empty on the last page.
+	Next string `json:"next,omitempty"`
 }
 
 // WalletSummaries is an alphabetically sorted list of wallet summaries.
@@ -86,3 +132,112 @@ type WalletSummary struct {
 	Consumed    string `json:"consumed"`
 	Default     bool   `json:"default,omitempty"`
 }
+
+// Permission is a level of access to a wallet granted to a user or
+// group other than its owner.
+type Permission string
+
+const (
+	// ReadPermission allows the wallet and its budgets to be viewed.
+	ReadPermission Permission = "read"
+	// SpendPermission allows budgets to be created from the wallet's
+	// funds. It implies ReadPermission.
+	SpendPermission Permission = "spend"
+)
+
+// Validate checks that the permission is a known permission level.
+func (p Permission) Validate() error {
+	switch p {
+	case ReadPermission, SpendPermission:
+		return nil
+	}
+	return errors.NotValidf("permission %q", string(p))
+}
+
+// WalletGrant represents the access to a wallet granted to a user
+// or group.
+type WalletGrant struct {
+	Subject    string     `json:"subject"`
+	Permission Permission `json:"permission"`
+}
+
+// ListWalletGrantsResponse is returned by the ListWalletGrants API call.
+type ListWalletGrantsResponse struct {
+	Grants []WalletGrant `json:"grants"`
+}
+
+// LedgerEntryType is the kind of a ledger entry.
+type LedgerEntryType string
+
+const (
+	// DebitEntry records funds consumed by a model.
+	DebitEntry LedgerEntryType = "debit"
+	// CreditEntry records funds added to a wallet.
+	CreditEntry LedgerEntryType = "credit"
+	// LimitChangeEntry records a change to a wallet or budget limit.
+	LimitChangeEntry LedgerEntryType = "limit-change"
+)
+
+// LedgerEntry represents a single time-stamped change to a wallet
+// or budget.
+type LedgerEntry struct {
+	Time        time.Time       `json:"time"`
+	Type        LedgerEntryType `json:"type"`
+	Wallet      string          `json:"wallet"`
+	Model       string          `json:"model,omitempty"`
+	Amount      string          `json:"amount"`
+	Balance     string          `json:"balance,omitempty"`
+	Description string          `json:"description,omitempty"`
+}
+
+// LedgerResponse is returned by the Ledger API call.
+type LedgerResponse struct {
+	Entries []LedgerEntry `json:"entries"`
+	// Next holds the cursor used to request the next page of
+	// entries. It is empty on the last page.
+	Next string `json:"next,omitempty"`
+}
+
+// CreditSourceType is the origin of a credit.
+type CreditSourceType string
+
+const (
+	// TopUpCredit is credit purchased by the user.
+	TopUpCredit CreditSourceType = "top-up"
+	// PromotionalCredit is credit granted by the service.
+	PromotionalCredit CreditSourceType = "promotional"
+	// RefundCredit is credit returned to the user.
+	RefundCredit CreditSourceType = "refund"
+)
+
+// CreditSource represents a single addition to the user's credit.
+type CreditSource struct {
+	Type        CreditSourceType `json:"type"`
+	Amount      string           `json:"amount"`
+	Remaining   string           `json:"remaining"`
+	Reference   string           `json:"reference,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Time        time.Time        `json:"time"`
+	// Expires holds the time at which any remaining credit expires,
+	// and is nil for credit that does not expire.
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+// CreditAllocation represents the credit applied to a wallet.
+type CreditAllocation struct {
+	Wallet string `json:"wallet"`
+	Amount string `json:"amount"`
+	// Consumed holds the part of the allocated credit that has
+	// been spent.
+	Consumed string `json:"consumed"`
+}
+
+// CreditResponse is returned by the GetCredit API call.
+type CreditResponse struct {
+	// Balance holds the user's total remaining credit.
+	Balance string `json:"balance"`
+	// Unallocated holds the credit not yet applied to any wallet.
+	Unallocated string             `json:"unallocated"`
+	Sources     []CreditSource     `json:"sources,omitempty"`
+	Allocations []CreditAllocation `json:"allocations,omitempty"`
+}
@@ -52,3 +52,11 @@ func (t *BudgetSuite) TestBudgetSorting(c *gc.C) {
 	sort.Sort(budget.SortedBudgets(budgets))
 	c.Assert(budgets, gc.DeepEquals, expected)
 }
+
+func (t *BudgetSuite) TestEnforcementPolicyValidate(c *gc.C) {
+	for _, p := range []budget.EnforcementPolicy{budget.NotifyPolicy, budget.BlockPolicy, budget.DegradePolicy} {
+		c.Check(p.Validate(), gc.IsNil)
+	}
+	c.Assert(budget.EnforcementPolicy("").Validate(), gc.ErrorMatches, `enforcement policy "" not valid`)
+	c.Assert(budget.EnforcementPolicy("shutdown").Validate(), gc.ErrorMatches, `enforcement policy "shutdown" not valid`)
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package budget_test
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/romulus/wireformat/budget"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+type goldenSuite struct{}
+
+var _ = gc.Suite(&goldenSuite{})
+
+var goldenTests = []struct {
+	file  string
+	value interface{}
+}{{
+	file: "wallet-with-budgets.json",
+	value: &budget.WalletWithBudgets{
+		Limit: "4000.00",
+		Total: budget.WalletTotals{
+			Budgeted:    "2200.00",
+			Unallocated: "1800.00",
+			Available:   "1100.00",
+			Consumed:    "1100.0",
+			Usage:       "50%",
+		},
+		Budgets: []budget.Budget{{
+			Owner:    "user.joe",
+			Limit:    "1200.00",
+			Consumed: "500.00",
+			Usage:    "42%",
+			Model:    "model.joe",
+		}, {
+			Owner:    "user.jess",
+			Limit:    "1000",
+			Consumed: "600.00",
+			Usage:    "60%",
+			Model:    "model.jess",
+		}},
+	},
+}, {
+	file: "wallet-with-budgets-empty.json",
+	value: &budget.WalletWithBudgets{
+		Total: budget.WalletTotals{
+			Budgeted:    "0.00",
+			Unallocated: "0.00",
+			Available:   "0.00",
+			Consumed:    "0.00",
+			Usage:       "0%",
+		},
+	},
+}, {
+	file: "list-wallets-response.json",
+	value: &budget.ListWalletsResponse{
+		Wallets: budget.WalletSummaries{{
+			Owner:       "bob",
+			Wallet:      "personal",
+			Limit:       "50",
+			Budgeted:    "30",
+			Unallocated: "20",
+			Available:   "45",
+			Consumed:    "5",
+			Default:     true,
+		}, {
+			Owner:       "bob",
+			Wallet:      "work",
+			Limit:       "200",
+			Budgeted:    "100",
+			Unallocated: "100",
+			Available:   "150",
+			Consumed:    "50",
+		}},
+		Total: budget.WalletTotals{
+			Limit:       "250",
+			Budgeted:    "130",
+			Available:   "195",
+			Unallocated: "120",
+			Consumed:    "55",
+		},
+		Credit: "400",
+	},
+}, {
+	file: "list-wallets-response-empty.json",
+	value: &budget.ListWalletsResponse{
+		Total: budget.WalletTotals{
+			Budgeted:    "0",
+			Available:   "0",
+			Unallocated: "0",
+			Consumed:    "0",
+		},
+	},
+}, {
+	file: "wallet-totals.json",
+	value: &budget.WalletTotals{
+		Limit:       "250",
+		Budgeted:    "130",
+		Available:   "195",
+		Unallocated: "120",
+		Usage:       "22%",
+		Consumed:    "55",
+	},
+}, {
+	file: "wallet-totals-no-limit.json",
+	value: &budget.WalletTotals{
+		Budgeted:    "130",
+		Available:   "195",
+		Unallocated: "120",
+		Usage:       "22%",
+		Consumed:    "55",
+	},
+}}
+
+func (s *goldenSuite) TestEncode(c *gc.C) {
+	for i, test := range goldenTests {
+		c.Logf("test %d: %s", i, test.file)
+		data, err := json.MarshalIndent(test.value, "", "  ")
+		c.Assert(err, jc.ErrorIsNil)
+		data = append(data, '\n')
+		path := filepath.Join("testdata", test.file)
+		if *updateGolden {
+			err := ioutil.WriteFile(path, data, 0644)
+			c.Assert(err, jc.ErrorIsNil)
+		}
+		golden, err := ioutil.ReadFile(path)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(data), gc.Equals, string(golden))
+	}
+}
+
+func (s *goldenSuite) TestDecode(c *gc.C) {
+	for i, test := range goldenTests {
+		c.Logf("test %d: %s", i, test.file)
+		golden, err := ioutil.ReadFile(filepath.Join("testdata", test.file))
+		c.Assert(err, jc.ErrorIsNil)
+		value := reflect.New(reflect.TypeOf(test.value).Elem()).Interface()
+		err = json.Unmarshal(golden, value)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(value, jc.DeepEquals, test.value)
+	}
+}
+
+func (s *goldenSuite) TestDecodeCaseInsensitive(c *gc.C) {
+	// Older services sent capitalised field names, which must
+	// still be accepted.
+	var w budget.WalletWithBudgets
+	err := json.Unmarshal([]byte(`{"Limit": "100", "Budgets": [{"Model": "model-a"}]}`), &w)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w, jc.DeepEquals, budget.WalletWithBudgets{
+		Limit:   "100",
+		Budgets: []budget.Budget{{Model: "model-a"}},
+	})
+}
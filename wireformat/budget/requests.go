@@ -3,6 +3,12 @@
 
 package budget
 
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
 // CreateWalletRequest is used in the requests to the budget service
 // for creating the specified wallet.
 type CreateWalletRequest struct {
@@ -28,14 +34,20 @@ func (CreateWalletRequest) URL(apiRoot string) string {
 
 // ListWalletsRequest defines a request to the budgets service
 // to list a user's wallets.
-type ListWalletsRequest struct{}
+type ListWalletsRequest struct {
+	// Cursor holds the cursor returned with the previous page.
+	Cursor string `json:"-"`
+	// Limit holds the maximum number of wallets to return. Zero
+	// uses the service default.
+	Limit int `json:"-"`
+}
 
 // Method returns the method of the request.
 func (ListWalletsRequest) Method() string { return "GET" }
 
 // URL returns the URL of the request.
-func (ListWalletsRequest) URL(apiRoot string) string {
-	return apiRoot + "/wallet"
+func (r ListWalletsRequest) URL(apiRoot string) string {
+	return pageURL(apiRoot+"/wallet", r.Cursor, r.Limit)
 }
 
 // SetWalletRequest defines a request that updates the limit of
@@ -63,24 +75,151 @@ func (r SetWalletRequest) URL(apiRoot string) string {
 	return apiRoot + "/wallet/" + r.Wallet
 }
 
+// DeleteWalletRequest defines a request that removes a wallet.
+type DeleteWalletRequest struct {
+	Wallet string `json:"-"`
+	// Force requests that the wallet is deleted even if it
+	// still has budgets.
+	Force bool `json:"-"`
+}
+
+// Method returns the method of the request.
+func (DeleteWalletRequest) Method() string { return "DELETE" }
+
+// URL returns the URL for the request.
+func (r DeleteWalletRequest) URL(apiRoot string) string {
+	if r.Force {
+		return apiRoot + "/wallet/" + r.Wallet + "?force=true"
+	}
+	return apiRoot + "/wallet/" + r.Wallet
+}
+
+// RenameWalletRequest defines a request that changes the name of
+// a wallet.
+type RenameWalletRequest struct {
+	Wallet string `json:"-"`
+	Name   string `json:"name"`
+}
+
+// ContentType return the content-type header to be set for the request.
+func (RenameWalletRequest) ContentType() string { return "application/json" }
+
+// Method returns the method of the request.
+func (RenameWalletRequest) Method() string { return "PATCH" }
+
+// Body returns the request body.
+func (r RenameWalletRequest) Body() interface{} {
+	return struct {
+		Update RenameWalletRequest `json:"update"`
+	}{Update: r}
+}
+
+// URL returns the URL for the request.
+func (r RenameWalletRequest) URL(apiRoot string) string {
+	return apiRoot + "/wallet/" + r.Wallet
+}
+
+// SetDefaultWalletRequest defines a request that makes a wallet the
+// user's default wallet.
+type SetDefaultWalletRequest struct {
+	Wallet  string `json:"-"`
+	Default bool   `json:"default"`
+}
+
+// ContentType return the content-type header to be set for the request.
+func (SetDefaultWalletRequest) ContentType() string { return "application/json" }
+
+// Method returns the method of the request.
+func (SetDefaultWalletRequest) Method() string { return "PATCH" }
+
+// Body returns the request body.
+func (r SetDefaultWalletRequest) Body() interface{} {
+	return struct {
+		Update SetDefaultWalletRequest `json:"update"`
+	}{Update: r}
+}
+
+// URL returns the URL for the request.
+func (r SetDefaultWalletRequest) URL(apiRoot string) string {
+	return apiRoot + "/wallet/" + r.Wallet
+}
+
 // GetWalletRequest defines a request that retrieves a specific wallet.
 type GetWalletRequest struct {
 	Wallet string
+	// Cursor holds the cursor returned with the previous page
+	// of budgets.
+	Cursor string `json:"-"`
+	// Limit holds the maximum number of budgets to return. Zero
+	// uses the service default.
+	Limit int `json:"-"`
 }
 
 // URL returns the URL for the request.
 func (r GetWalletRequest) URL(apiRoot string) string {
-	return apiRoot + "/wallet/" + r.Wallet
+	return pageURL(apiRoot+"/wallet/"+r.Wallet, r.Cursor, r.Limit)
 }
 
 // Method returns the method for the request.
 func (GetWalletRequest) Method() string { return "GET" }
 
+// GrantWalletRequest defines a request that grants a user or group
+// access to a wallet.
+type GrantWalletRequest struct {
+	Wallet     string     `json:"-"`
+	Subject    string     `json:"subject"`
+	Permission Permission `json:"permission"`
+}
+
+// ContentType return the content-type header to be set for the request.
+func (GrantWalletRequest) ContentType() string { return "application/json" }
+
+// Method returns the method for the request.
+func (GrantWalletRequest) Method() string { return "POST" }
+
+// Body returns the request body.
+func (r GrantWalletRequest) Body() interface{} { return r }
+
+// URL returns the URL for the request.
+func (r GrantWalletRequest) URL(apiRoot string) string {
+	return apiRoot + "/wallet/" + r.Wallet + "/grant"
+}
+
+// RevokeWalletRequest defines a request that revokes the access to
+// a wallet granted to a user or group.
+type RevokeWalletRequest struct {
+	Wallet  string `json:"-"`
+	Subject string `json:"-"`
+}
+
+// Method returns the method for the request.
+func (RevokeWalletRequest) Method() string { return "DELETE" }
+
+// URL returns the URL for the request.
+func (r RevokeWalletRequest) URL(apiRoot string) string {
+	return apiRoot + "/wallet/" + r.Wallet + "/grant/" + url.PathEscape(r.Subject)
+}
+
+// ListWalletGrantsRequest defines a request that lists the users and
+// groups granted access to a wallet.
+type ListWalletGrantsRequest struct {
+	Wallet string `json:"-"`
+}
+
+// Method returns the method for the request.
+func (ListWalletGrantsRequest) Method() string { return "GET" }
+
+// URL returns the URL for the request.
+func (r ListWalletGrantsRequest) URL(apiRoot string) string {
+	return apiRoot + "/wallet/" + r.Wallet + "/grant"
+}
+
 // CreateBudgetRequest defines a request to create an budget in the specified wallet.
 type CreateBudgetRequest struct {
-	Model  string `json:"model"`
-	Limit  string `json:"limit"`
-	Wallet string `json:"-"`
+	Model       string            `json:"model"`
+	Limit       string            `json:"limit"`
+	Wallet      string            `json:"-"`
+	Enforcement EnforcementPolicy `json:"enforcement,omitempty"`
 }
 
 // URL returns the URL for the request.
@@ -100,9 +239,11 @@ func (r CreateBudgetRequest) Body() interface{} { return r }
 // UpdateBudgetRequest defines a request to update a budget
 // associated with a model.
 type UpdateBudgetRequest struct {
-	Model  string `json:"-"`
-	Limit  string `json:"limit,omitempty"`
-	Wallet string `json:"wallet,omitempty"`
+	Model           string            `json:"-"`
+	Limit           string            `json:"limit,omitempty"`
+	Wallet          string            `json:"wallet,omitempty"`
+	AlertThresholds []int             `json:"alert-thresholds,omitempty"`
+	Enforcement     EnforcementPolicy `json:"enforcement,omitempty"`
 }
 
 // ContentType return the content-type header to be set for the request.
@@ -136,3 +277,167 @@ func (r DeleteBudgetRequest) URL(apiRoot string) string {
 
 // Method returns the method for the request.
 func (DeleteBudgetRequest) Method() string { return "DELETE" }
+
+// GetBudgetRequest defines a request that retrieves the budget
+// associated with a model.
+type GetBudgetRequest struct {
+	Model string `json:"-"`
+}
+
+// URL returns the URL for the request.
+func (r GetBudgetRequest) URL(apiRoot string) string {
+	return apiRoot + "/model/" + r.Model + "/budget"
+}
+
+// Method returns the method for the request.
+func (GetBudgetRequest) Method() string { return "GET" }
+
+// ListBudgetsRequest defines a request to list the budgets visible
+// to the user. Empty filters match all budgets.
+type ListBudgetsRequest struct {
+	// Owner restricts the list to budgets owned by the user.
+	Owner string `json:"-"`
+	// Wallet restricts the list to budgets in the wallet.
+	Wallet string `json:"-"`
+	// OverLimit restricts the list to budgets that have consumed
+	// more than their limit.
+	OverLimit bool `json:"-"`
+}
+
+// URL returns the URL for the request.
+func (r ListBudgetsRequest) URL(apiRoot string) string {
+	query := url.Values{}
+	if r.Owner != "" {
+		query.Set("owner", r.Owner)
+	}
+	if r.Wallet != "" {
+		query.Set("wallet", r.Wallet)
+	}
+	if r.OverLimit {
+		query.Set("over-limit", "true")
+	}
+	if len(query) == 0 {
+		return apiRoot + "/budget"
+	}
+	return apiRoot + "/budget?" + query.Encode()
+}
+
+// Method returns the method for the request.
+func (ListBudgetsRequest) Method() string { return "GET" }
+
+// LedgerRequest defines a request for the ledger of a wallet or of
+// the budget associated with a model.
+type LedgerRequest struct {
+	// Wallet holds the wallet whose ledger is requested.
+	Wallet string `json:"-"`
+	// Model holds the model whose budget ledger is requested. It
+	// takes precedence over Wallet.
+	Model string `json:"-"`
+	// From and To restrict the entries to the given time range.
+	// Zero values leave the range open.
+	From time.Time `json:"-"`
+	To   time.Time `json:"-"`
+	// Cursor holds the cursor returned with the previous page.
+	Cursor string `json:"-"`
+	// Limit holds the maximum number of entries to return. Zero
+	// uses the service default.
+	Limit int `json:"-"`
+}
+
+// URL returns the URL for the request.
+func (r LedgerRequest) URL(apiRoot string) string {
+	u := apiRoot + "/wallet/" + r.Wallet + "/ledger"
+	if r.Model != "" {
+		u = apiRoot + "/model/" + r.Model + "/ledger"
+	}
+	query := url.Values{}
+	if !r.From.IsZero() {
+		query.Set("from", r.From.UTC().Format(time.RFC3339))
+	}
+	if !r.To.IsZero() {
+		query.Set("to", r.To.UTC().Format(time.RFC3339))
+	}
+	if r.Cursor != "" {
+		query.Set("cursor", r.Cursor)
+	}
+	if r.Limit > 0 {
+		query.Set("limit", strconv.Itoa(r.Limit))
+	}
+	if len(query) == 0 {
+		return u
+	}
+	return u + "?" + query.Encode()
+}
+
+// Method returns the method for the request.
+func (LedgerRequest) Method() string { return "GET" }
+
+// pageURL adds the pagination parameters, if any, to the URL.
+func pageURL(u, cursor string, limit int) string {
+	query := url.Values{}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if len(query) == 0 {
+		return u
+	}
+	return u + "?" + query.Encode()
+}
+
+// GetCreditRequest defines a request that retrieves the user's
+// credit balance.
+type GetCreditRequest struct{}
+
+// URL returns the URL for the request.
+func (GetCreditRequest) URL(apiRoot string) string {
+	return apiRoot + "/credit"
+}
+
+// Method returns the method for the request.
+func (GetCreditRequest) Method() string { return "GET" }
+
+// TopUpRequest defines a request that records a top-up of the
+// user's credit.
+type TopUpRequest struct {
+	Amount string `json:"amount"`
+	// Reference holds the payment reference of the top-up.
+	Reference string `json:"reference,omitempty"`
+}
+
+// ContentType return the content-type header to be set for the request.
+func (TopUpRequest) ContentType() string { return "application/json" }
+
+// Method returns the method for the request.
+func (TopUpRequest) Method() string { return "POST" }
+
+// Body returns the request body.
+func (r TopUpRequest) Body() interface{} { return r }
+
+// URL returns the URL for the request.
+func (TopUpRequest) URL(apiRoot string) string {
+	return apiRoot + "/credit/top-up"
+}
+
+// AllocateCreditRequest defines a request that applies some of the
+// user's unallocated credit to a wallet.
+type AllocateCreditRequest struct {
+	Wallet string `json:"-"`
+	Amount string `json:"amount"`
+}
+
+// ContentType return the content-type header to be set for the request.
+func (AllocateCreditRequest) ContentType() string { return "application/json" }
+
+// Method returns the method for the request.
+func (AllocateCreditRequest) Method() string { return "POST" }
+
+// Body returns the request body.
+func (r AllocateCreditRequest) Body() interface{} { return r }
+
+// URL returns the URL for the request.
+func (r AllocateCreditRequest) URL(apiRoot string) string {
+	return apiRoot + "/wallet/" + r.Wallet + "/credit"
+}
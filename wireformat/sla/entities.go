@@ -5,19 +5,92 @@
 package sla
 
 import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/v3"
 	"gopkg.in/macaroon.v2"
 )
 
 // SLARequest defines the json used to post to sla service.
 type SLARequest struct {
 	ModelUUID string `json:"model"`
-	Level     string `json:"sla"`
+	Level     Level  `json:"sla"`
 	Budget    string `json:"budget"`
 }
 
+// Validate checks the SLARequest for errors.
+func (r SLARequest) Validate() error {
+	if !utils.IsValidUUIDString(r.ModelUUID) {
+		return errors.NotValidf("model UUID %q", r.ModelUUID)
+	}
+	if err := r.Level.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
 // SLAResponse defines the json response when an sla is set.
 type SLAResponse struct {
 	Owner       string             `json:"owner"`
 	Credentials *macaroon.Macaroon `json:"credentials"`
 	Message     string             `json:"message,omitempty"`
+	// Budget holds the wallet and budget limit the sla is funded
+	// from, if known.
+	Budget *BudgetAssociation `json:"budget,omitempty"`
+}
+
+// SLA describes the sla currently set for a model.
+type SLA struct {
+	ModelUUID string `json:"model"`
+	Owner     string `json:"owner"`
+	Level     Level  `json:"sla"`
+	Budget    string `json:"budget,omitempty"`
+	// CredentialsExpiry holds the time at which the sla
+	// credentials issued for the model expire.
+	CredentialsExpiry time.Time `json:"credentials-expiry"`
+}
+
+// SupportLevel describes a support level available to a user.
+type SupportLevel struct {
+	Name        Level  `json:"name"`
+	Description string `json:"description"`
+	Price       string `json:"price"`
+}
+
+// BudgetAssociation describes the wallet and budget limit an sla is
+// funded from.
+type BudgetAssociation struct {
+	Wallet string `json:"wallet"`
+	Limit  string `json:"limit"`
+}
+
+// ParseBudget parses an sla budget of the form [<wallet>:]<limit>. The
+// returned wallet is empty if the budget does not name one.
+func ParseBudget(budget string) (BudgetAssociation, error) {
+	var b BudgetAssociation
+	if i := strings.LastIndex(budget, ":"); i >= 0 {
+		b.Wallet, b.Limit = budget[:i], budget[i+1:]
+		if b.Wallet == "" {
+			return BudgetAssociation{}, errors.NotValidf("budget %q", budget)
+		}
+	} else {
+		b.Limit = budget
+	}
+	limit, err := strconv.ParseFloat(b.Limit, 64)
+	if err != nil || limit < 0 || math.IsNaN(limit) || math.IsInf(limit, 0) {
+		return BudgetAssociation{}, errors.NotValidf("budget limit %q", b.Limit)
+	}
+	return b, nil
+}
+
+// String returns the budget in [<wallet>:]<limit> form.
+func (b BudgetAssociation) String() string {
+	if b.Wallet == "" {
+		return b.Limit
+	}
+	return b.Wallet + ":" + b.Limit
 }
@@ -0,0 +1,133 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sla_test
+
+import (
+	"testing"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/romulus/wireformat/sla"
+)
+
+func Test(t *testing.T) {
+	gc.TestingT(t)
+}
+
+type SLASuite struct{}
+
+var _ = gc.Suite(&SLASuite{})
+
+func (s *SLASuite) TestParseLevel(c *gc.C) {
+	for _, name := range []string{"unsupported", "essential", "standard", "advanced"} {
+		level, err := sla.ParseLevel(name)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(level.String(), gc.Equals, name)
+	}
+	_, err := sla.ParseLevel("premium")
+	c.Assert(err, gc.ErrorMatches, `sla level "premium" not valid`)
+	_, err = sla.ParseLevel("")
+	c.Assert(err, gc.ErrorMatches, `sla level "" not valid`)
+}
+
+func (s *SLASuite) TestCompare(c *gc.C) {
+	tests := []struct {
+		a, b     sla.Level
+		expected int
+	}{
+		{sla.Essential, sla.Standard, -1},
+		{sla.Advanced, sla.Standard, 1},
+		{sla.Standard, sla.Standard, 0},
+		{sla.Unsupported, sla.Essential, -1},
+		{sla.Level("premium"), sla.Unsupported, -1},
+	}
+	for i, test := range tests {
+		c.Logf("test %d: %s vs %s", i, test.a, test.b)
+		c.Check(test.a.Compare(test.b), gc.Equals, test.expected)
+	}
+}
+
+func (s *SLASuite) TestValidateRequest(c *gc.C) {
+	tests := []struct {
+		about   string
+		request sla.SLARequest
+		err     string
+	}{{
+		about: "valid request",
+		request: sla.SLARequest{
+			ModelUUID: "0f4c3bb0-33a2-4e2b-8d61-6a7f5cde3c44",
+			Level:     sla.Essential,
+		},
+	}, {
+		about: "invalid model uuid",
+		request: sla.SLARequest{
+			ModelUUID: "not-a-uuid",
+			Level:     sla.Essential,
+		},
+		err: `model UUID "not-a-uuid" not valid`,
+	}, {
+		about: "unknown level",
+		request: sla.SLARequest{
+			ModelUUID: "0f4c3bb0-33a2-4e2b-8d61-6a7f5cde3c44",
+			Level:     sla.Level("premium"),
+		},
+		err: `sla level "premium" not valid`,
+	}}
+	for i, test := range tests {
+		c.Logf("test %d: %s", i, test.about)
+		err := test.request.Validate()
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *SLASuite) TestParseBudget(c *gc.C) {
+	tests := []struct {
+		budget   string
+		expected sla.BudgetAssociation
+		err      string
+	}{{
+		budget:   "10",
+		expected: sla.BudgetAssociation{Limit: "10"},
+	}, {
+		budget:   "personal:10.5",
+		expected: sla.BudgetAssociation{Wallet: "personal", Limit: "10.5"},
+	}, {
+		budget: ":10",
+		err:    `budget ":10" not valid`,
+	}, {
+		budget: "personal:-1",
+		err:    `budget limit "-1" not valid`,
+	}, {
+		budget: "personal:",
+		err:    `budget limit "" not valid`,
+	}, {
+		budget: "NaN",
+		err:    `budget limit "NaN" not valid`,
+	}, {
+		budget: "personal:NaN",
+		err:    `budget limit "NaN" not valid`,
+	}, {
+		budget: "personal:Inf",
+		err:    `budget limit "Inf" not valid`,
+	}, {
+		budget: "personal:-Inf",
+		err:    `budget limit "-Inf" not valid`,
+	}}
+	for i, test := range tests {
+		c.Logf("test %d: %q", i, test.budget)
+		b, err := sla.ParseBudget(test.budget)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(b, gc.Equals, test.expected)
+		c.Check(b.String(), gc.Equals, test.budget)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sla
+
+import (
+	"github.com/juju/errors"
+)
+
+// Level is an sla support level.
+type Level string
+
+const (
+	// Unsupported indicates that the model has no support.
+	Unsupported Level = "unsupported"
+	// Essential is the lowest paid support level.
+	Essential Level = "essential"
+	// Standard is the intermediate support level.
+	Standard Level = "standard"
+	// Advanced is the highest support level.
+	Advanced Level = "advanced"
+)
+
+// levelRanks orders the known support levels from lowest to highest.
+var levelRanks = map[Level]int{
+	Unsupported: 0,
+	Essential:   1,
+	Standard:    2,
+	Advanced:    3,
+}
+
+// ParseLevel returns the support level with the given name.
+func ParseLevel(s string) (Level, error) {
+	l := Level(s)
+	if err := l.Validate(); err != nil {
+		return "", errors.Trace(err)
+	}
+	return l, nil
+}
+
+// Validate checks that the level is a known support level.
+func (l Level) Validate() error {
+	if _, ok := levelRanks[l]; !ok {
+		return errors.NotValidf("sla level %q", string(l))
+	}
+	return nil
+}
+
+// Compare returns -1, 0 or 1 depending on whether l is a lower, the
+// same or a higher support level than other. Unknown levels rank
+// below all known levels.
+func (l Level) Compare(other Level) int {
+	rank := func(l Level) int {
+		if r, ok := levelRanks[l]; ok {
+			return r
+		}
+		return -1
+	}
+	switch r, o := rank(l), rank(other); {
+	case r < o:
+		return -1
+	case r > o:
+		return 1
+	}
+	return 0
+}
+
+// String implements fmt.Stringer.
+func (l Level) String() string {
+	return string(l)
+}